@@ -0,0 +1,128 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// requestGapRgx matches what might separate a chain of addresses from the HTTP request which it precedes,
+// i.a. ident, user and time of the request logged by eventlet.wsgi: 10.0.0.1 - - [07/Dec/2016 10:59:19] "GET / HTTP/1.0"
+var requestGapRgx = regexp.MustCompile(`^\s*(\S+\s+\S+\s+\[[^\]]*\]\s*)?$`)
+
+// getHTTPRequestAddresses returns client and server IP addresses found in msg based on regular expression
+// `httpRequestAddressesRgx`; when it captures a chain of addresses (`http_addresses`), the chain which
+// immediately precedes the HTTP request matched by `httpRequestContextRgx` is used if it consists only
+// of valid IP addresses, so addresses elsewhere in the message are not picked up
+func (p *parser) getHTTPRequestAddresses(msg string) (map[string]string, error) {
+	rgx := p.httpRequestAddressesRgx
+	names := rgx.SubexpNames()
+
+	request := p.httpRequestContextRgx.FindStringIndex(msg)
+	if request == nil {
+		return nil, errors.New("No HTTP request found")
+	}
+
+	for start := 0; start < len(msg); {
+		loc := rgx.FindStringSubmatchIndex(msg[start:])
+		if loc == nil {
+			break
+		}
+
+		fields := map[string]string{}
+		chainEnd := 0
+		for i, name := range names {
+			if name == "" || i == 0 || loc[2*i] < 0 {
+				continue
+			}
+			if val := msg[start+loc[2*i] : start+loc[2*i+1]]; val != "" {
+				fields[name] = val
+			}
+			if name == "http_addresses" {
+				chainEnd = loc[2*i+1]
+			}
+		}
+
+		chain, exist := fields["http_addresses"]
+		if !exist {
+			// user-defined pattern which captures addresses directly
+			return fields, nil
+		}
+		delete(fields, "http_addresses")
+
+		if start+chainEnd > request[0] {
+			break
+		}
+		addresses, ok := parseAddressChain(chain)
+		if !ok || !requestGapRgx.MatchString(msg[start+chainEnd:request[0]]) {
+			// the boundary following the chain might precede the next one, so continue from the chain's end
+			start += chainEnd
+			continue
+		}
+
+		fields["http_client_ip_address"] = addresses[0]
+		if len(addresses) > 1 {
+			fields["http_server_ip_address"] = addresses[len(addresses)-1]
+		}
+		if len(addresses) > 2 {
+			fields["http_forwarded_chain"] = strings.Join(addresses, ",")
+		}
+		return fields, nil
+	}
+
+	return nil, errors.New("No valid IP addresses found")
+}
+
+// parseAddressChain splits a comma-separated chain of addresses and returns them without brackets and ports;
+// false is returned if any of them is not a valid IP address
+func parseAddressChain(chain string) ([]string, bool) {
+	addresses := []string{}
+	for _, address := range strings.Split(chain, ",") {
+		address = trimPort(strings.TrimSpace(address))
+
+		// zone is not a part of IP address, but it is kept in the returned address
+		ip := address
+		if i := strings.Index(ip, "%"); i >= 0 {
+			ip = ip[:i]
+		}
+		if net.ParseIP(ip) == nil {
+			return nil, false
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, true
+}
+
+// trimPort removes brackets and a port from the address, i.a. "[2001:db8::1]:8774" -> "2001:db8::1",
+// "10.0.0.1:8774" -> "10.0.0.1"
+func trimPort(address string) string {
+	if strings.HasPrefix(address, "[") {
+		if i := strings.Index(address, "]"); i > 0 {
+			return address[1:i]
+		}
+		return address
+	}
+	if strings.Count(address, ":") == 1 {
+		return address[:strings.Index(address, ":")]
+	}
+	return address
+}
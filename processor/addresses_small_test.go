@@ -0,0 +1,94 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseAddressChain(t *testing.T) {
+	Convey("Parse chain of addresses", t, func() {
+		Convey("should accept IPv4 and IPv6 addresses", func() {
+			addresses, ok := parseAddressChain("10.91.126.38, 2001:db8::7,[fe80::1%eth0]:8774,10.0.0.1:8774")
+			So(ok, ShouldBeTrue)
+			So(addresses, ShouldResemble, []string{"10.91.126.38", "2001:db8::7", "fe80::1%eth0", "10.0.0.1"})
+		})
+		Convey("should reject chain with invalid address", func() {
+			_, ok := parseAddressChain("10.91.126.38,10.0.0.300")
+			So(ok, ShouldBeFalse)
+		})
+		Convey("should reject values which only look like addresses", func() {
+			for _, chain := range []string{"0.4711170", "200", "b1ad1df9062a4fc682904c6c9b0f4e98", "dead:beef"} {
+				_, ok := parseAddressChain(chain)
+				So(ok, ShouldBeFalse)
+			}
+		})
+	})
+}
+
+func TestGetHTTPRequestAddresses(t *testing.T) {
+	Convey("Create a logs-openstack processor", t, func() {
+		processor := New()
+		So(processor, ShouldNotBeNil)
+
+		Convey("should return an error when there are no addresses", func() {
+			_, err := processor.getHTTPRequestAddresses("\"GET / HTTP/1.1\" status: 200 len: 23011 time: 0.4711170")
+			So(err, ShouldNotBeNil)
+		})
+		Convey("should not pick up an address from URL", func() {
+			_, err := processor.getHTTPRequestAddresses("wsgi starting up on http://10.0.0.1:8774")
+			So(err, ShouldNotBeNil)
+		})
+		Convey("should return IPv6 client and server addresses", func() {
+			output, err := processor.getHTTPRequestAddresses("[-] 2001:db8::7,[fe80::1%eth0] \"GET / HTTP/1.1\" status: 200 len: 23011 time: 0.4711170")
+			So(err, ShouldBeNil)
+			So(output, ShouldResemble, map[string]string{
+				"http_client_ip_address": "2001:db8::7",
+				"http_server_ip_address": "fe80::1%eth0",
+			})
+		})
+		Convey("should return forwarded chain", func() {
+			output, err := processor.getHTTPRequestAddresses("[req-0c0b761c-47b0-4bf5-832c-89ef048fa56a b1ad1df9062a4fc682904c6c9b0f4e98 " +
+				"fa2b2986c200431b8119035d4a47d420 - - -] 203.0.113.7, 10.1.1.1,10.0.0.1 \"GET / HTTP/1.1\" status: 200 len: 23011 time: 0.4711170")
+			So(err, ShouldBeNil)
+			So(output, ShouldResemble, map[string]string{
+				"http_client_ip_address": "203.0.113.7",
+				"http_server_ip_address": "10.0.0.1",
+				"http_forwarded_chain":   "203.0.113.7,10.1.1.1,10.0.0.1",
+			})
+		})
+		Convey("should only use the chain which immediately precedes the request", func() {
+			output, err := processor.getHTTPRequestAddresses("Reply from 10.0.0.9 to 10.91.126.38 \"GET / HTTP/1.1\" status: 200 len: 23011 time: 0.4711170")
+			So(err, ShouldBeNil)
+			So(output, ShouldResemble, map[string]string{"http_client_ip_address": "10.91.126.38"})
+
+			_, err = processor.getHTTPRequestAddresses("Reply from 10.0.0.9 to client \"GET / HTTP/1.1\" status: 200 len: 23011 time: 0.4711170")
+			So(err, ShouldNotBeNil)
+		})
+		Convey("should skip a candidate which is not an address", func() {
+			output, err := processor.getHTTPRequestAddresses("deadbeef 10.0.0.2 \"GET / HTTP/1.1\" 200 276 0.000323")
+			So(err, ShouldBeNil)
+			So(output["http_client_ip_address"], ShouldEqual, "10.0.0.2")
+		})
+	})
+}
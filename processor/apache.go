@@ -0,0 +1,96 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	// ***	PATTERN FOR APACHE ACCESS LOG   ***
+	// 	Services hosted by Apache mod_wsgi (i.a. keystone, horizon, placement) write access logs in the common
+	// 	or the combined log format, optionally followed or preceded by time taken to serve the request (%D):
+	// 	%h %l %u %t "%r" %>s %b
+	// 	%h %l %u %t "%r" %>s %b "%{Referer}i" "%{User-agent}i"
+	// 	%h %l %u %t "%r" %>s %b %D "%{Referer}i" "%{User-agent}i"
+	// 	%h %l %u %t "%r" %>s %b "%{Referer}i" "%{User-agent}i" %D(us)
+	//
+	// 	Example:	10.0.0.1 - admin [07/Dec/2016:03:53:55 +0000] "GET /v3/auth/tokens HTTP/1.1" 200 2326 "-" "python-keystoneclient" 4711
+	//
+	// 	**Notice** that the request line might be logged as "-", i.a. when a connection is closed before a request comes
+	apacheLogRegexp = `^(?P<http_client_ip_address>\S+) \S+ (?P<remote_user>\S+) \[(?P<timestamp>[^\]]+)\] ` +
+		`"((?P<http_method>[A-Z]+) (?P<http_url>\S+) HTTP/(?P<http_version>[\d.]+)|[^"]*)" ` +
+		`(?P<http_status>\d{3}) (?P<http_response_size>\d+|-)` +
+		`( (?P<response_time_us>\d+)(\(us\))?)?` +
+		`( "(?P<http_referrer>[^"]*)" "(?P<http_user_agent>[^"]*)")?` +
+		`( (?P<trailing_response_time_us>\d+)(\(us\))?)?`
+
+	// apacheRecordStartRegexp matches a beginning of access log, each line is a separate log
+	apacheRecordStartRegexp = `^\S+ \S+ \S+ \[[^\]]+\] "`
+
+	apacheTimeFormat = "02/Jan/2006:15:04:05 -0700"
+)
+
+var (
+	apacheLogRgx         = regexp.MustCompile(apacheLogRegexp)
+	apacheRecordStartRgx = regexp.MustCompile(apacheRecordStartRegexp)
+)
+
+// apacheFormat is a format of Apache access logs
+type apacheFormat struct{}
+
+// parse returns fields of an access log, the whole line is kept as log's message
+func (apacheFormat) parse(p *parser, data string) (timestamp time.Time, msg string, fields map[string]string, err error) {
+	fields, err = parse(data, apacheLogRgx)
+	if err != nil {
+		return
+	}
+
+	timestamp, err = time.Parse(apacheTimeFormat, fields["timestamp"])
+	if err != nil {
+		return
+	}
+	timestamp = timestamp.In(p.location)
+	delete(fields, "timestamp")
+
+	// values which are not known are logged as `-`
+	for _, name := range []string{"remote_user", "http_response_size", "http_referrer", "http_user_agent"} {
+		if fields[name] == "-" {
+			delete(fields, name)
+		}
+	}
+
+	// time taken to serve the request is logged in microseconds, but it is stored in seconds
+	// as `http_response_time` retrieved from Openstack logs
+	for _, name := range []string{"response_time_us", "trailing_response_time_us"} {
+		if us, exist := fields[name]; exist {
+			microseconds, _ := strconv.ParseInt(us, 10, 64)
+			fields["http_response_time"] = strconv.FormatFloat(float64(microseconds)/1e6, 'f', -1, 64)
+			delete(fields, name)
+		}
+	}
+
+	return timestamp, data, fields, nil
+}
+
+func (apacheFormat) startsRecord(line string) bool {
+	return apacheRecordStartRgx.MatchString(line)
+}
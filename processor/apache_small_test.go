@@ -0,0 +1,89 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestApacheFormat(t *testing.T) {
+	Convey("Create logs-openstack processor", t, func() {
+		processor := New()
+		So(processor, ShouldNotBeNil)
+		format := apacheFormat{}
+
+		Convey("access log in combined format should be parsed", func() {
+			data := `10.0.0.1 - admin [07/Dec/2016:03:26:24 +0100] "POST /v3/auth/tokens HTTP/1.1" 201 2326 "-" "python-keystoneclient"`
+			timestamp, msg, fields, err := format.parse(processor.parser, data)
+			So(err, ShouldBeNil)
+			So(timestamp.UTC(), ShouldResemble, time.Date(2016, 12, 7, 2, 26, 24, 0, time.UTC))
+			So(msg, ShouldEqual, data)
+			So(fields, ShouldResemble, map[string]string{
+				"http_client_ip_address": "10.0.0.1",
+				"remote_user":            "admin",
+				"http_method":            "POST",
+				"http_url":               "/v3/auth/tokens",
+				"http_version":           "1.1",
+				"http_status":            "201",
+				"http_response_size":     "2326",
+				"http_user_agent":        "python-keystoneclient",
+			})
+		})
+		Convey("access log in common format should be parsed", func() {
+			_, _, fields, err := format.parse(processor.parser, `10.0.0.1 - - [07/Dec/2016:03:26:24 +0000] "GET /dashboard/ HTTP/1.1" 302 -`)
+			So(err, ShouldBeNil)
+			So(fields, ShouldResemble, map[string]string{
+				"http_client_ip_address": "10.0.0.1",
+				"http_method":            "GET",
+				"http_url":               "/dashboard/",
+				"http_version":           "1.1",
+				"http_status":            "302",
+			})
+		})
+		Convey("time taken to serve the request should be stored in seconds", func() {
+			for _, data := range []string{
+				`10.0.0.1 - - [07/Dec/2016:03:26:24 +0000] "GET /placement HTTP/1.1" 200 405 471117 "http://10.0.0.1/" "curl/7.29.0"`,
+				`10.0.0.1 - - [07/Dec/2016:03:26:24 +0000] "GET /placement HTTP/1.1" 200 405 "http://10.0.0.1/" "curl/7.29.0" 471117(us)`,
+			} {
+				_, _, fields, err := format.parse(processor.parser, data)
+				So(err, ShouldBeNil)
+				So(fields["http_response_time"], ShouldEqual, "0.471117")
+				So(fields["http_referrer"], ShouldEqual, "http://10.0.0.1/")
+				So(fields["http_user_agent"], ShouldEqual, "curl/7.29.0")
+				So(fields, ShouldNotContainKey, "response_time_us")
+				So(fields, ShouldNotContainKey, "trailing_response_time_us")
+			}
+		})
+		Convey("access log without request line should be parsed", func() {
+			_, _, fields, err := format.parse(processor.parser, `10.0.0.1 - - [07/Dec/2016:03:26:24 +0000] "-" 408 -`)
+			So(err, ShouldBeNil)
+			So(fields["http_status"], ShouldEqual, "408")
+			So(fields, ShouldNotContainKey, "http_method")
+		})
+		Convey("log in other format should give an error", func() {
+			_, _, _, err := format.parse(processor.parser, "2016-12-07 03:26:24.254 7 INFO nova.console.websocketproxy [-] handler exception")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
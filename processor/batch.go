@@ -0,0 +1,110 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ***	BATCHED LOGS   ***
+// 	Some collectors batch several log records in one metric, its data might be a string, []byte or []string (i.a. one line
+// 	per element). Records are split at lines which start a log in the format selected for the log file or, if the format
+// 	is auto-detected, in the first format which a log might start with the first line; i.a. a record of Openstack log
+// 	starts at a line beginning with the log prefix (timestamp followed by pid) or at a line holding JSON formatted log,
+// 	so following lines without the prefix (e.g. lines of traceback) stay in the record.
+// 	Lines wrapped by a container runtime (see container.go) are split in the same way based on the wrapped content.
+
+// recordStartRegexp matches a line starting a new record of Openstack log
+const recordStartRegexp = `^` + timestampRegexp + `[ ]\d+[ ]`
+
+var recordStartRgx = regexp.MustCompile(recordStartRegexp)
+
+// getRecords returns log records carried by metric's data, an error is returned for data of unexpected type
+func (p *parser) getRecords(logFile string, data interface{}) ([]string, error) {
+	var batch string
+	switch d := data.(type) {
+	case string:
+		batch = d
+	case []byte:
+		batch = string(d)
+	case []string:
+		batch = strings.Join(d, "\n")
+	default:
+		return nil, fmt.Errorf("Unexpected data type %T", data)
+	}
+	return p.splitRecords(logFile, batch), nil
+}
+
+// splitRecords splits batched logs of the log file into records, data containing one record is returned untouched
+func (p *parser) splitRecords(logFile string, batch string) []string {
+	if !strings.Contains(batch, "\n") {
+		return []string{batch}
+	}
+
+	lines := strings.Split(batch, "\n")
+	format := p.getRecordFormat(logFile, lines[0])
+	records := []string{}
+	start := 0
+	for i := 1; i < len(lines); i++ {
+		if format.startsRecord(unwrapLine(lines[i])) {
+			records = appendRecord(records, lines[start:i])
+			start = i
+		}
+	}
+	if start == 0 {
+		return []string{batch}
+	}
+	return appendRecord(records, lines[start:])
+}
+
+// getRecordFormat returns the format selected for the log file or, if it is auto-detected, the first format which
+// a log might start with the line; the Openstack format is returned if there is no such format
+func (p *parser) getRecordFormat(logFile string, line string) logFormat {
+	if format := p.getFormat(logFile); format != autoFormatName {
+		return logFormats[format]
+	}
+
+	line = unwrapLine(line)
+	for _, format := range autoDetectedFormats {
+		if logFormats[format].startsRecord(line) {
+			return logFormats[format]
+		}
+	}
+	return logFormats[openstackFormatName]
+}
+
+// unwrapLine returns the line without a wrapper of container runtime, if there is one
+func unwrapLine(line string) string {
+	if unwrapped, _, _, ok := unwrapContainerLine(line); ok {
+		return unwrapped
+	}
+	return line
+}
+
+// appendRecord appends a record made of lines to records, blank lines ending the record are omitted
+// as they separate records and an empty record is skipped
+func appendRecord(records []string, lines []string) []string {
+	record := strings.TrimRight(strings.Join(lines, "\n"), "\r\n")
+	if strings.TrimSpace(record) == "" {
+		return records
+	}
+	return append(records, record)
+}
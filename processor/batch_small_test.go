@@ -0,0 +1,138 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetRecords(t *testing.T) {
+	Convey("Get log records from metric's data", t, func() {
+		processor := New()
+		So(processor, ShouldNotBeNil)
+
+		first := "2016-12-07 03:26:24.254 7 INFO nova.console.websocketproxy [-] handler exception"
+		second := "2016-12-07 03:26:25.120 7 ERROR nova.api.openstack.extensions [-] Unexpected exception in API method\n" +
+			"Traceback (most recent call last):\n" +
+			"  File \"/usr/lib/python2.7/site-packages/nova/api/openstack/extensions.py\", line 478, in wrapped"
+
+		Convey("should return an error for data of unexpected type", func() {
+			_, err := processor.getRecords("nova-api.log", 1)
+			So(err, ShouldNotBeNil)
+		})
+		Convey("should return one record for a single log", func() {
+			records, err := processor.getRecords("nova-api.log", first)
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, []string{first})
+		})
+		Convey("should keep lines of traceback in one record", func() {
+			records, err := processor.getRecords("nova-api.log", second)
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, []string{second})
+		})
+		Convey("should split batched logs into records", func() {
+			records, err := processor.getRecords("nova-api.log", first+"\n"+second+"\n")
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, []string{first, second})
+		})
+		Convey("should accept []byte", func() {
+			records, err := processor.getRecords("nova-api.log", []byte(first+"\r\n"+second))
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, []string{first, second})
+		})
+		Convey("should accept []string with lines of records", func() {
+			records, err := processor.getRecords("nova-api.log", append([]string{first}, strings.Split(second, "\n")...))
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, []string{first, second})
+		})
+		Convey("should split batched JSON formatted logs", func() {
+			json := `{"message": "some_message", "created": 1481167129.626, "levelname": "ERROR"}`
+			records, err := processor.getRecords("nova-api.log", json+"\n"+json)
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, []string{json, json})
+		})
+		Convey("should split batched logs wrapped by a container runtime", func() {
+			lines := strings.Split(second, "\n")
+			records, err := processor.getRecords("nova-api.log", []string{
+				"2016-12-07T03:26:24.254953Z stdout F " + first,
+				"2016-12-07T03:26:25.120953Z stderr F " + lines[0],
+				"2016-12-07T03:26:25.120960Z stderr F " + lines[1],
+				"2016-12-07T03:26:25.120967Z stderr F " + lines[2],
+			})
+			So(err, ShouldBeNil)
+			So(records, ShouldHaveLength, 2)
+			So(records[1], ShouldStartWith, "2016-12-07T03:26:25.120953Z stderr F "+lines[0])
+		})
+		Convey("should split batched access logs of Apache", func() {
+			access := []string{
+				`10.0.0.1 - admin [07/Dec/2016:03:26:24 +0000] "POST /v3/auth/tokens HTTP/1.1" 201 2326 "-" "python-keystoneclient"`,
+				`10.0.0.2 - - [07/Dec/2016:03:26:25 +0000] "GET /v3 HTTP/1.1" 200 267 "-" "curl/7.29.0"`,
+			}
+			records, err := processor.getRecords("keystone-access.log", strings.Join(access, "\n"))
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, access)
+		})
+		Convey("should split batched logs of RabbitMQ", func() {
+			logs := []string{
+				"2016-12-07 03:26:24.254 [info] <0.197.0> one",
+				"2016-12-07 03:26:25.120 [error] <0.1330.0> two\n  continued",
+				"=INFO REPORT==== 7-Dec-2016::03:26:26 ===\naccepting AMQP connection <0.1330.0> (10.0.0.1:49738 -> 10.0.0.1:5672)",
+			}
+			records, err := processor.getRecords("rabbit@controller.log", strings.Join(logs, "\n"))
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, logs)
+		})
+		Convey("should split batched logs of MariaDB", func() {
+			logs := []string{
+				"2016-12-07  3:26:24 140234567890 [Note] WSREP: Synchronized with group, ready for connections",
+				"161207  3:26:25 [Warning] Aborted connection 42 to db: 'nova'",
+			}
+			records, err := processor.getRecords("mysqld.log", strings.Join(logs, "\n"))
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, logs)
+		})
+		Convey("should split batched syslog messages", func() {
+			logs := []string{
+				"<11>Dec  8 03:18:49 controller-1 nova-api[20]: ERROR nova.api.openstack.extensions one",
+				"<11>1 2016-12-08T03:18:50.120Z controller-1 nova-api 20 - - ERROR nova.api.openstack.extensions two",
+			}
+			records, err := processor.getRecords("nova-api.log", strings.Join(logs, "\n"))
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, logs)
+		})
+		Convey("should split records in the format selected for the log file", func() {
+			prs, err := processor.parser.configure(map[string]string{logFormatsConfig: "rabbit*=rabbitmq"})
+			So(err, ShouldBeNil)
+			records, err := prs.getRecords("rabbit@controller.log", "garbage\n2016-12-07 03:26:24.254 [info] <0.197.0> one")
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, []string{"garbage", "2016-12-07 03:26:24.254 [info] <0.197.0> one"})
+		})
+		Convey("should keep report of RabbitMQ in one record", func() {
+			report := "=INFO REPORT==== 8-Dec-2016::03:18:49 ===\naccepting AMQP connection <0.1.0> (10.0.0.1:5672 -> 10.0.0.2:5672)"
+			records, err := processor.getRecords("rabbit@controller.log", report)
+			So(err, ShouldBeNil)
+			So(records, ShouldResemble, []string{report})
+		})
+	})
+}
@@ -0,0 +1,414 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/intelsdi-x/snap-plugin-lib-go/v1/plugin"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// timezoneConfig is a name of config item which sets timezone of log timestamps, it might be an IANA name
+	// (e.g. "Europe/Warsaw"), "UTC", "Local" or a fixed offset (e.g. "+02:00", "-0500", "UTC+2");
+	// when it is not set, the timezone of the host is used
+	timezoneConfig = "timezone"
+
+	// names of config items which override built-in regular expressions, see patterns declared in processor.go
+	logRegexpConfig                  = "log_regexp"
+	requestContextRegexpConfig       = "request_context_regexp"
+	httpRequestContextRegexpConfig   = "http_request_context_regexp"
+	httpRequestAddressesRegexpConfig = "http_request_addresses_regexp"
+
+	// severityMappingConfig is a name of config item which adds or overrides mapping of severity labels onto levels
+	// in a form of comma separated list, e.g. "AUDIT=INFO,VERBOSE=7"
+	severityMappingConfig = "severity_mapping"
+	// unknownSeverityConfig is a name of config item which sets handling of unknown severity labels,
+	// see modes declared in severity.go
+	unknownSeverityConfig = "unknown_severity"
+	// logFormatsConfig is a name of config item which selects log formats for log files, see formats.go
+	logFormatsConfig = "log_formats"
+
+	// names of config items which define how a logger is retrieved from metric's namespace, see logger.go
+	loggerElementConfig  = "logger_element"
+	loggerRegexpConfig   = "logger_regexp"
+	loggerTemplateConfig = "logger_template"
+	// logFileNamingConfig is a name of config item which sets naming convention of log files, see logger.go
+	logFileNamingConfig = "log_file_naming"
+
+	// hostnameElementConfig is a name of config item which sets a name of namespace element holding a hostname
+	hostnameElementConfig = "hostname_element"
+	// hostnameConfig is a name of config item which sets a hostname used when namespace does not hold it
+	hostnameConfig = "hostname"
+
+	// redactionRegexpConfig is a name of config item which sets a regular expression of sensitive data redacted
+	// in addition to built-in rules, see redaction.go
+	redactionRegexpConfig = "redaction_regexp"
+
+	// multilineTracebackConfig is a name of config item which enables joining lines of a traceback into one metric
+	multilineTracebackConfig = "multiline_traceback"
+	// httpMetricsConfig is a name of config item which enables emitting numeric metrics derived from HTTP request context
+	httpMetricsConfig = "http_metrics"
+	// correlationConfig is a name of config item which enables correlation of requests across services
+	correlationConfig = "correlation"
+	// statsMetricsConfig is a name of config item which enables emitting statistics of parsing as metrics
+	statsMetricsConfig = "stats_metrics"
+	// fingerprintConfig is a name of config item which enables tagging metrics with template and fingerprint of message
+	fingerprintConfig = "fingerprint"
+	// suppressionConfig is a name of config item which enables suppression of repetitive logs
+	suppressionConfig = "suppression"
+	// redactionConfig is a name of config item which enables redaction of sensitive data
+	redactionConfig = "redaction"
+
+	// correlationTTLConfig is a name of config item which sets time (in seconds) after which a request not seen
+	// anymore is removed from correlation index
+	correlationTTLConfig = "correlation_ttl"
+	// correlationSizeConfig is a name of config item which sets maximum number of requests in correlation index
+	correlationSizeConfig = "correlation_size"
+	// suppressionWindowConfig is a name of config item which sets a period (in seconds) in which repetitions
+	// of a log are counted
+	suppressionWindowConfig = "suppression_window"
+	// suppressionLimitConfig is a name of config item which sets a number of repetitions of a log emitted
+	// within the period
+	suppressionLimitConfig = "suppression_limit"
+
+	// onParseErrorConfig is a name of config item which sets handling of metrics which cannot be processed,
+	// see modes declared in parse_error.go
+	onParseErrorConfig = "on_parse_error"
+
+	// names of config items which set the least and the most severe logs which are emitted, see severity.go
+	minSeverityConfig = "min_severity"
+	maxSeverityConfig = "max_severity"
+)
+
+// configNamespace is a namespace of config rules declared by the processor
+var configNamespace = []string{""}
+
+// parserConfigs lists config items which customize a parser
+var parserConfigs = []string{
+	timezoneConfig,
+	logRegexpConfig,
+	requestContextRegexpConfig,
+	httpRequestContextRegexpConfig,
+	httpRequestAddressesRegexpConfig,
+	severityMappingConfig,
+	unknownSeverityConfig,
+	logFormatsConfig,
+	loggerElementConfig,
+	loggerRegexpConfig,
+	loggerTemplateConfig,
+	logFileNamingConfig,
+	hostnameElementConfig,
+	hostnameConfig,
+	redactionRegexpConfig,
+}
+
+// optionConfigs lists bool config items which enable optional processing
+var optionConfigs = []string{
+	multilineTracebackConfig,
+	httpMetricsConfig,
+	correlationConfig,
+	statsMetricsConfig,
+	fingerprintConfig,
+	suppressionConfig,
+	redactionConfig,
+}
+
+// options holds optional processing enabled by the task config
+type options struct {
+	multilineTraceback bool
+	httpMetrics        bool
+	correlation        bool
+	correlationTTL     time.Duration
+	correlationSize    int
+	statsMetrics       bool
+	fingerprint        bool
+	suppression        bool
+	suppressionWindow  time.Duration
+	suppressionLimit   int
+	redaction          bool
+	onParseError       string
+	minSeverity        *severityBound
+	maxSeverity        *severityBound
+}
+
+// fixedOffsetRgx matches timezone defined as a fixed offset from UTC, i.a. "+02:00", "-0500", "UTC+2", "GMT-03:30"
+var fixedOffsetRgx = regexp.MustCompile(`^(UTC|GMT)?(?P<sign>[+-])(?P<hours>\d{1,2})(:?(?P<minutes>\d{2}))?$`)
+
+// getParser returns a parser configured according to the task config, parsers are created once per distinct
+// configuration and cached; the default parser is returned when config does not override anything
+func (p *Plugin) getParser(cfg plugin.Config) (*parser, error) {
+	settings := map[string]string{}
+	key := ""
+	for _, name := range parserConfigs {
+		val, err := getConfigString(cfg, name)
+		if err != nil {
+			return nil, err
+		}
+		if val != "" {
+			settings[name] = val
+			key += fmt.Sprintf("%s=%q;", name, val)
+		}
+	}
+	if len(settings) == 0 {
+		return p.parser, nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if prs, exist := p.parsers[key]; exist {
+		return prs, nil
+	}
+
+	prs, err := p.parser.configure(settings)
+	if err != nil {
+		return nil, err
+	}
+	p.parsers[key] = prs
+
+	log.WithFields(log.Fields{
+		"_block":    "getParser",
+		"_settings": settings,
+	}).Info("Created parser for task configuration")
+
+	return prs, nil
+}
+
+// configure returns a copy of the parser with the location and regular expressions overridden by settings
+func (p *parser) configure(settings map[string]string) (*parser, error) {
+	prs := *p
+
+	for name, val := range settings {
+		var err error
+		switch name {
+		case timezoneConfig:
+			prs.location, err = loadLocation(val)
+		case logRegexpConfig:
+			prs.logRgx, err = compilePattern(val, "timestamp", "payload")
+		case requestContextRegexpConfig:
+			prs.requestContextRgx, err = compilePattern(val)
+		case httpRequestContextRegexpConfig:
+			prs.httpRequestContextRgx, err = compilePattern(val)
+		case httpRequestAddressesRegexpConfig:
+			prs.httpRequestAddressesRgx, err = compilePattern(val)
+		case severityMappingConfig:
+			prs.severities, err = parseSeverityMapping(val)
+		case logFormatsConfig:
+			prs.formatRules, err = parseFormatRules(val)
+		case loggerElementConfig:
+			prs.loggerElement = val
+		case loggerRegexpConfig:
+			prs.loggerRgx, err = compilePattern(val, "service")
+		case loggerTemplateConfig:
+			prs.loggerTemplate = val
+		case logFileNamingConfig:
+			prs.logFileNaming, err = checkLogFileNaming(val)
+		case hostnameElementConfig:
+			prs.hostnameElement = val
+		case hostnameConfig:
+			prs.hostname = val
+		case redactionRegexpConfig:
+			prs.redactionRgx, err = compilePattern(val)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value of config item `%s`: %v", name, err)
+		}
+	}
+
+	// handling of unknown severity labels is checked when the mapping of labels is already set
+	if val, exist := settings[unknownSeverityConfig]; exist {
+		var err error
+		if prs.unknownSeverity, err = checkUnknownSeverity(val, prs.severities); err != nil {
+			return nil, fmt.Errorf("Invalid value of config item `%s`: %v", unknownSeverityConfig, err)
+		}
+	}
+
+	return &prs, nil
+}
+
+// compilePattern compiles a user-defined regular expression and checks if it contains required named groups
+func compilePattern(pattern string, requiredGroups ...string) (*regexp.Regexp, error) {
+	rgx, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for _, name := range rgx.SubexpNames() {
+		names[name] = true
+	}
+	for _, group := range requiredGroups {
+		if !names[group] {
+			return nil, fmt.Errorf("regular expression `%s` does not contain required named group `%s`", pattern, group)
+		}
+	}
+	return rgx, nil
+}
+
+// getOptions returns optional processing enabled by the task config, severity labels are looked up in the mapping
+// of the parser configured for the task
+func getOptions(cfg plugin.Config, prs *parser) (*options, error) {
+	var err error
+	opts := &options{}
+
+	if opts.multilineTraceback, err = getConfigBool(cfg, multilineTracebackConfig); err != nil {
+		return nil, err
+	}
+	if opts.httpMetrics, err = getConfigBool(cfg, httpMetricsConfig); err != nil {
+		return nil, err
+	}
+	if opts.correlation, err = getConfigBool(cfg, correlationConfig); err != nil {
+		return nil, err
+	}
+	if opts.statsMetrics, err = getConfigBool(cfg, statsMetricsConfig); err != nil {
+		return nil, err
+	}
+	if opts.fingerprint, err = getConfigBool(cfg, fingerprintConfig); err != nil {
+		return nil, err
+	}
+	if opts.suppression, err = getConfigBool(cfg, suppressionConfig); err != nil {
+		return nil, err
+	}
+	if opts.redaction, err = getConfigBool(cfg, redactionConfig); err != nil {
+		return nil, err
+	}
+
+	ttl, err := getConfigInt(cfg, correlationTTLConfig, defaultCorrelationTTL)
+	if err != nil {
+		return nil, err
+	}
+	opts.correlationTTL = time.Duration(ttl) * time.Second
+
+	size, err := getConfigInt(cfg, correlationSizeConfig, defaultCorrelationSize)
+	if err != nil {
+		return nil, err
+	}
+	opts.correlationSize = int(size)
+
+	window, err := getConfigInt(cfg, suppressionWindowConfig, defaultSuppressionWindow)
+	if err != nil {
+		return nil, err
+	}
+	opts.suppressionWindow = time.Duration(window) * time.Second
+
+	limit, err := getConfigInt(cfg, suppressionLimitConfig, defaultSuppressionLimit)
+	if err != nil {
+		return nil, err
+	}
+	opts.suppressionLimit = int(limit)
+
+	if opts.onParseError, err = getConfigString(cfg, onParseErrorConfig); err != nil {
+		return nil, err
+	}
+	if opts.onParseError == "" {
+		opts.onParseError = parseErrorPassthrough
+	}
+	if err = checkParseErrorMode(opts.onParseError); err != nil {
+		return nil, err
+	}
+
+	if opts.minSeverity, err = getConfigSeverityBound(cfg, minSeverityConfig, prs.severities); err != nil {
+		return nil, err
+	}
+	if opts.maxSeverity, err = getConfigSeverityBound(cfg, maxSeverityConfig, prs.severities); err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
+
+// getConfigString returns a value of string config item or an empty string if the item is not set
+func getConfigString(cfg plugin.Config, key string) (string, error) {
+	val, err := cfg.GetString(key)
+	if err == plugin.ErrConfigNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("Invalid value of config item `%s`: %v", key, err)
+	}
+	return val, nil
+}
+
+// getConfigBool returns a value of bool config item or false if the item is not set
+func getConfigBool(cfg plugin.Config, key string) (bool, error) {
+	val, err := cfg.GetBool(key)
+	if err == plugin.ErrConfigNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("Invalid value of config item `%s`: %v", key, err)
+	}
+	return val, nil
+}
+
+// getConfigInt returns a value of int config item, which is expected to be positive, or the default value
+// if the item is not set
+func getConfigInt(cfg plugin.Config, key string, defaultValue int64) (int64, error) {
+	val, err := cfg.GetInt(key)
+	if err == plugin.ErrConfigNotFound {
+		return defaultValue, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("Invalid value of config item `%s`: %v", key, err)
+	}
+	if val <= 0 {
+		return 0, fmt.Errorf("Invalid value of config item `%s`: %d is not positive", key, val)
+	}
+	return val, nil
+}
+
+// getConfigSeverityBound returns a bound of severity set by config item or nil if the item is not set,
+// labels are looked up in the mapping
+func getConfigSeverityBound(cfg plugin.Config, key string, mapping map[string]int) (*severityBound, error) {
+	val, err := getConfigString(cfg, key)
+	if err != nil {
+		return nil, err
+	}
+	bound, err := parseSeverityBound(val, mapping)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid value of config item `%s`: %v", key, err)
+	}
+	return bound, nil
+}
+
+// loadLocation returns a location for the given timezone, which might be an IANA name or a fixed offset from UTC
+func loadLocation(timezone string) (*time.Location, error) {
+	offset, err := parse(timezone, fixedOffsetRgx)
+	if err != nil {
+		// not a fixed offset, so expect an IANA name
+		return time.LoadLocation(timezone)
+	}
+
+	hours, _ := strconv.Atoi(offset["hours"])
+	minutes, _ := strconv.Atoi(offset["minutes"])
+	if hours > 14 || minutes > 59 {
+		return nil, fmt.Errorf("offset `%s` is out of range", timezone)
+	}
+
+	seconds := (hours*60 + minutes) * 60
+	if offset["sign"] == "-" {
+		seconds = -seconds
+	}
+	return time.FixedZone(timezone, seconds), nil
+}
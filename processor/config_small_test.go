@@ -0,0 +1,137 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/intelsdi-x/snap-plugin-lib-go/v1/plugin"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLoadLocation(t *testing.T) {
+	Convey("Load location for timezone", t, func() {
+		Convey("defined as IANA name", func() {
+			location, err := loadLocation("America/New_York")
+			So(err, ShouldBeNil)
+			So(location.String(), ShouldEqual, "America/New_York")
+		})
+		Convey("defined as UTC", func() {
+			location, err := loadLocation("UTC")
+			So(err, ShouldBeNil)
+			So(location, ShouldEqual, time.UTC)
+		})
+		Convey("defined as fixed offsets", func() {
+			for timezone, expected := range map[string]int{
+				"+02:00":    2 * 60 * 60,
+				"-0500":     -5 * 60 * 60,
+				"UTC+2":     2 * 60 * 60,
+				"GMT-03:30": -(3*60 + 30) * 60,
+			} {
+				location, err := loadLocation(timezone)
+				So(err, ShouldBeNil)
+				_, offset := time.Date(2016, 12, 8, 0, 0, 0, 0, location).Zone()
+				So(offset, ShouldEqual, expected)
+			}
+		})
+		Convey("should return an error for unknown timezone", func() {
+			_, err := loadLocation("Mars/Olympus_Mons")
+			So(err, ShouldNotBeNil)
+		})
+		Convey("should return an error for offset out of range", func() {
+			_, err := loadLocation("+25:00")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestGetParser(t *testing.T) {
+	Convey("Create logs-openstack processor", t, func() {
+		processor := New()
+		So(processor, ShouldNotBeNil)
+
+		Convey("default parser should be returned when timezone is not set", func() {
+			prs, err := processor.getParser(nil)
+			So(err, ShouldBeNil)
+			So(prs, ShouldEqual, processor.parser)
+			So(prs.location, ShouldEqual, time.Local)
+		})
+		Convey("parser should be created once per distinct configuration", func() {
+			prs1, err := processor.getParser(plugin.Config{"timezone": "UTC"})
+			So(err, ShouldBeNil)
+			So(prs1.location, ShouldEqual, time.UTC)
+			prs2, err := processor.getParser(plugin.Config{"timezone": "UTC"})
+			So(err, ShouldBeNil)
+			So(prs2, ShouldEqual, prs1)
+		})
+		Convey("should return an error when timezone is not a string", func() {
+			_, err := processor.getParser(plugin.Config{"timezone": int64(1)})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestCompilePattern(t *testing.T) {
+	Convey("Compile user-defined regular expression", t, func() {
+		Convey("should succeed for valid pattern with required named groups", func() {
+			rgx, err := compilePattern(`(?P<timestamp>\S+ \S+) (?P<payload>.*)`, "timestamp", "payload")
+			So(err, ShouldBeNil)
+			So(rgx, ShouldNotBeNil)
+		})
+		Convey("should return an error for invalid pattern", func() {
+			_, err := compilePattern(`(?P<timestamp>\S+`)
+			So(err, ShouldNotBeNil)
+		})
+		Convey("should return an error for pattern without required named group", func() {
+			_, err := compilePattern(`(?P<timestamp>\S+ \S+) (?P<message>.*)`, "timestamp", "payload")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "payload")
+		})
+	})
+}
+
+func TestConfigureParser(t *testing.T) {
+	Convey("Create logs-openstack processor", t, func() {
+		processor := New()
+		So(processor, ShouldNotBeNil)
+
+		Convey("configured parser should override only given settings", func() {
+			prs, err := processor.parser.configure(map[string]string{
+				requestContextRegexpConfig: `\[(?P<request_id>req-\S+)`,
+			})
+			So(err, ShouldBeNil)
+			So(prs.requestContextRgx.String(), ShouldEqual, `\[(?P<request_id>req-\S+)`)
+			So(prs.logRgx, ShouldEqual, processor.logRgx)
+			So(prs.location, ShouldEqual, processor.location)
+			Convey("and the default parser should be unchanged", func() {
+				So(processor.requestContextRgx.String(), ShouldEqual, requestContextRegexp)
+			})
+		})
+		Convey("configuring parser should fail for invalid log pattern", func() {
+			_, err := processor.parser.configure(map[string]string{
+				logRegexpConfig: `(?P<timestamp>\S+) (?P<msg>.*)`,
+			})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, logRegexpConfig)
+		})
+	})
+}
@@ -0,0 +1,109 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// ***	CONTAINER LOG WRAPPERS   ***
+// 	Logs of containerized services (e.g. deployed by Kolla-Ansible or openstack-helm) might be wrapped by a container runtime,
+// 	each line written by a service is stored as a separate entry in one of the forms:
+//
+// 	a) Docker json-file logging driver, the line is partial unless it ends with a new line:
+// 	{"log":"2016-12-08 03:18:49.626 20 ERROR nova.api.openstack.extensions some_message\n","stream":"stderr","time":"2016-12-08T03:18:49.626953Z"}
+//
+// 	b) CRI (containerd, CRI-O), the line is full (F) or partial (P):
+// 	2016-12-08T03:18:49.626953Z stderr F 2016-12-08 03:18:49.626 20 ERROR nova.api.openstack.extensions some_message
+//
+// 	Wrappers are stripped, so the wrapped log is parsed in the same way as not wrapped one, and the stream and the time
+// 	of the first entry of log record are stored as `stream` and `container_time` tags.
+
+const (
+	criLogRegexp = `^(?P<container_time>\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})) ` +
+		`(?P<stream>stdout|stderr) (?P<cri_tag>[FP])( (?P<log>.*))?$`
+)
+
+var criLogRgx = regexp.MustCompile(criLogRegexp)
+
+// dockerLog holds fields of an entry written by Docker json-file logging driver
+type dockerLog struct {
+	Log    *string `json:"log"`
+	Stream string  `json:"stream"`
+	Time   string  `json:"time"`
+}
+
+// unwrapContainerLine returns a line of log wrapped by a container runtime, true if the line is partial,
+// tags of the wrapper and false if the entry is not wrapped
+func unwrapContainerLine(entry string) (line string, partial bool, tags map[string]string, ok bool) {
+	entry = strings.TrimRight(entry, "\r")
+
+	if isJSONLog(entry) {
+		dl := dockerLog{}
+		if err := json.Unmarshal([]byte(entry), &dl); err != nil || dl.Log == nil || dl.Stream == "" || dl.Time == "" {
+			return entry, false, nil, false
+		}
+		line = strings.TrimSuffix(*dl.Log, "\n")
+		partial = line == *dl.Log
+		return line, partial, map[string]string{"stream": dl.Stream, "container_time": dl.Time}, true
+	}
+
+	fields, err := parse(entry, criLogRgx)
+	if err != nil {
+		return entry, false, nil, false
+	}
+	return fields["log"], fields["cri_tag"] == "P", map[string]string{"stream": fields["stream"], "container_time": fields["container_time"]}, true
+}
+
+// unwrapContainerLog returns a log record with wrappers of a container runtime stripped from its lines, partial lines
+// are joined; tags of the first wrapper are returned as well, a record which is not wrapped is returned untouched
+func unwrapContainerLog(data string) (string, map[string]string) {
+	entries := strings.Split(data, "\n")
+	if _, _, _, ok := unwrapContainerLine(entries[0]); !ok {
+		return data, nil
+	}
+
+	var tags map[string]string
+	lines := []string{}
+	buf := ""
+	for _, entry := range entries {
+		line, partial, lineTags, ok := unwrapContainerLine(entry)
+		if !ok {
+			// an entry which is not wrapped, e.g. an empty line ending data, is kept unless it is empty
+			if strings.TrimSpace(entry) != "" {
+				lines = append(lines, entry)
+			}
+			continue
+		}
+		if tags == nil {
+			tags = lineTags
+		}
+		buf += line
+		if !partial {
+			lines = append(lines, buf)
+			buf = ""
+		}
+	}
+	if buf != "" {
+		lines = append(lines, buf)
+	}
+	return strings.Join(lines, "\n"), tags
+}
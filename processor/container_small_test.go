@@ -0,0 +1,71 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnwrapContainerLog(t *testing.T) {
+	Convey("Unwrap logs of containers", t, func() {
+		line := "2016-12-08 03:18:49.626 20 ERROR nova.api.openstack.extensions [-] Unexpected exception in API method"
+		tags := map[string]string{"stream": "stderr", "container_time": "2016-12-08T03:18:49.626953Z"}
+
+		Convey("should return not wrapped log untouched", func() {
+			data, wrapperTags := unwrapContainerLog(line)
+			So(data, ShouldEqual, line)
+			So(wrapperTags, ShouldBeNil)
+		})
+		Convey("should not unwrap JSON formatted log of Openstack", func() {
+			json := `{"message": "some_message", "created": 1481167129.626, "levelname": "ERROR"}`
+			data, wrapperTags := unwrapContainerLog(json)
+			So(data, ShouldEqual, json)
+			So(wrapperTags, ShouldBeNil)
+		})
+		Convey("should unwrap log of Docker json-file logging driver", func() {
+			data, wrapperTags := unwrapContainerLog(`{"log":"` + line + `\n","stream":"stderr","time":"2016-12-08T03:18:49.626953Z"}`)
+			So(data, ShouldEqual, line)
+			So(wrapperTags, ShouldResemble, tags)
+		})
+		Convey("should unwrap log of CRI", func() {
+			data, wrapperTags := unwrapContainerLog("2016-12-08T03:18:49.626953Z stderr F " + line)
+			So(data, ShouldEqual, line)
+			So(wrapperTags, ShouldResemble, tags)
+		})
+		Convey("should join lines of log and tag it with the first wrapper", func() {
+			data, wrapperTags := unwrapContainerLog("2016-12-08T03:18:49.626953Z stderr F " + line + "\n" +
+				"2016-12-08T03:18:49.627001Z stderr F Traceback (most recent call last):\n")
+			So(data, ShouldEqual, line+"\nTraceback (most recent call last):")
+			So(wrapperTags, ShouldResemble, tags)
+		})
+		Convey("should join partial lines", func() {
+			data, _ := unwrapContainerLog("2016-12-08T03:18:49.626953Z stderr P " + line[:30] + "\n" +
+				"2016-12-08T03:18:49.626960Z stderr F " + line[30:])
+			So(data, ShouldEqual, line)
+
+			data, _ = unwrapContainerLog(`{"log":"` + line[:30] + `","stream":"stderr","time":"2016-12-08T03:18:49.626953Z"}` + "\n" +
+				`{"log":"` + line[30:] + `\n","stream":"stderr","time":"2016-12-08T03:18:49.626960Z"}`)
+			So(data, ShouldEqual, line)
+		})
+	})
+}
@@ -0,0 +1,133 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"container/list"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/intelsdi-x/snap-plugin-lib-go/v1/plugin"
+)
+
+const (
+	// defaultCorrelationTTL is a default time (in seconds) after which a request not seen anymore is forgotten
+	defaultCorrelationTTL = 300
+	// defaultCorrelationSize is a default maximum number of requests held in correlation index
+	defaultCorrelationSize = 10000
+)
+
+// correlationIndex holds requests seen across Process calls, the index is bounded by a number of requests
+// and the least recently seen requests are evicted first
+type correlationIndex struct {
+	requests map[string]*list.Element
+	// order holds requests from the least to the most recently seen
+	order *list.List
+	mutex sync.Mutex
+	// now returns current time, it is used to expire requests
+	now func() time.Time
+}
+
+// correlatedRequest holds info about the first appearance of a request and services which it went through
+type correlatedRequest struct {
+	id           string
+	firstService string
+	firstSeen    time.Time
+	services     map[string]bool
+	lastAccess   time.Time
+}
+
+func newCorrelationIndex() *correlationIndex {
+	return &correlationIndex{
+		requests: map[string]*list.Element{},
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// correlate records appearance of the metric's request and returns tags describing the request:
+// `request_first_service`, `request_elapsed_time` (in seconds since the first appearance) and `request_hop_count`
+// (a number of services which the request went through so far); nil is returned if the metric has no request id
+// The global request id is preferred as it is passed between services, otherwise the local one is used
+func (c *correlationIndex) correlate(m plugin.Metric, ttl time.Duration, size int) map[string]string {
+	requestID := m.Tags["global_request_id"]
+	if requestID == "" {
+		requestID = m.Tags["request_id"]
+	}
+	if requestID == "" {
+		return nil
+	}
+	service := getService(m)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	c.expire(now, ttl)
+
+	var req *correlatedRequest
+	if elem, exist := c.requests[requestID]; exist {
+		req = elem.Value.(*correlatedRequest)
+		c.order.MoveToBack(elem)
+	} else {
+		req = &correlatedRequest{
+			id:           requestID,
+			firstService: service,
+			firstSeen:    m.Timestamp,
+			services:     map[string]bool{},
+		}
+		c.requests[requestID] = c.order.PushBack(req)
+		c.evict(size)
+	}
+	req.services[service] = true
+	req.lastAccess = now
+
+	elapsed := m.Timestamp.Sub(req.firstSeen)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	return map[string]string{
+		"request_first_service": req.firstService,
+		"request_elapsed_time":  strconv.FormatFloat(elapsed.Seconds(), 'f', -1, 64),
+		"request_hop_count":     strconv.Itoa(len(req.services)),
+	}
+}
+
+// expire removes requests which have not been seen for longer than ttl
+func (c *correlationIndex) expire(now time.Time, ttl time.Duration) {
+	for elem := c.order.Front(); elem != nil; elem = c.order.Front() {
+		req := elem.Value.(*correlatedRequest)
+		if now.Sub(req.lastAccess) <= ttl {
+			return
+		}
+		c.order.Remove(elem)
+		delete(c.requests, req.id)
+	}
+}
+
+// evict removes the least recently seen requests when the index exceeds its size
+func (c *correlationIndex) evict(size int) {
+	for c.order.Len() > size {
+		elem := c.order.Front()
+		c.order.Remove(elem)
+		delete(c.requests, elem.Value.(*correlatedRequest).id)
+	}
+}
@@ -0,0 +1,97 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/intelsdi-x/snap-plugin-lib-go/v1/plugin"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCorrelationIndex(t *testing.T) {
+	Convey("Create correlation index", t, func() {
+		index := newCorrelationIndex()
+		now := time.Date(2016, 12, 8, 3, 20, 0, 0, time.UTC)
+		index.now = func() time.Time { return now }
+
+		ts := time.Date(2016, 12, 8, 3, 18, 49, 0, time.UTC)
+		request := func(service string, requestID string, offset time.Duration) plugin.Metric {
+			mt := createMockMetric(service+"-api.log", "some_message")
+			mt.Timestamp = ts.Add(offset)
+			mt.Tags["logger"] = "openstack." + service
+			if requestID != "" {
+				mt.Tags["request_id"] = requestID
+			}
+			return mt
+		}
+		ttl := time.Minute
+
+		Convey("should return nil for metric without request id", func() {
+			So(index.correlate(request("nova", "", 0), ttl, 10), ShouldBeNil)
+		})
+		Convey("should tag the first appearance of request", func() {
+			tags := index.correlate(request("nova", "req-1", 0), ttl, 10)
+			So(tags, ShouldResemble, map[string]string{
+				"request_first_service": "nova",
+				"request_elapsed_time":  "0",
+				"request_hop_count":     "1",
+			})
+			Convey("and count services which the request went through", func() {
+				index.correlate(request("nova", "req-1", 100*time.Millisecond), ttl, 10)
+				index.correlate(request("neutron", "req-1", 1500*time.Millisecond), ttl, 10)
+				tags := index.correlate(request("glance", "req-1", 2250*time.Millisecond), ttl, 10)
+				So(tags, ShouldResemble, map[string]string{
+					"request_first_service": "nova",
+					"request_elapsed_time":  "2.25",
+					"request_hop_count":     "3",
+				})
+			})
+			Convey("and forget the request after ttl", func() {
+				now = now.Add(2 * ttl)
+				tags := index.correlate(request("neutron", "req-1", time.Second), ttl, 10)
+				So(tags["request_first_service"], ShouldEqual, "neutron")
+				So(tags["request_hop_count"], ShouldEqual, "1")
+			})
+		})
+		Convey("should prefer global request id", func() {
+			nova := request("nova", "req-1", 0)
+			nova.Tags["global_request_id"] = "req-global"
+			index.correlate(nova, ttl, 10)
+			neutron := request("neutron", "req-2", time.Second)
+			neutron.Tags["global_request_id"] = "req-global"
+			tags := index.correlate(neutron, ttl, 10)
+			So(tags["request_first_service"], ShouldEqual, "nova")
+			So(tags["request_hop_count"], ShouldEqual, "2")
+		})
+		Convey("should evict the least recently seen requests when size is exceeded", func() {
+			index.correlate(request("nova", "req-1", 0), ttl, 2)
+			index.correlate(request("nova", "req-2", 0), ttl, 2)
+			index.correlate(request("nova", "req-1", 0), ttl, 2)
+			index.correlate(request("nova", "req-3", 0), ttl, 2)
+			So(index.order.Len(), ShouldEqual, 2)
+			So(index.requests, ShouldContainKey, "req-1")
+			So(index.requests, ShouldNotContainKey, "req-2")
+			So(index.requests, ShouldContainKey, "req-3")
+		})
+	})
+}
@@ -0,0 +1,92 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+)
+
+const (
+	// ***	PATTERNS FOR MESSAGE TEMPLATE   ***
+	// 	Variable parts of a message are masked to get a template which is the same for all occurrences of an event:
+	//
+	// 	Example:	Instance 0c0b761c-47b0-4bf5-832c-89ef048fa56a failed to spawn on 10.0.0.1 after 3 attempts
+	// 	Template:	Instance <uuid> failed to spawn on <ip> after <num> attempts
+	//
+	// 	**Notice** that the leading request context is skipped, as it is stored in tags
+	leadingContextRegexp = `^\[[^\]]*\]\s*`
+	quotedRegexp         = `"[^"]*"|'[^']*'`
+	pathRegexp           = `(^|[\s(=])(/[\w.@%+~-]+)+/?`
+	// 	the uuid follows `uuidRegexp`, but consists of hexadecimal digits only, so it does not swallow i.a. words
+	uuidTokenRegexp = `\b[[:xdigit:]]{8}-?[[:xdigit:]]{4}-?[[:xdigit:]]{4}-?[[:xdigit:]]{4}-?[[:xdigit:]]{12}\b`
+	ipv4TokenRegexp = `\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`
+	ipv6TokenRegexp = `\[?\b([[:xdigit:]]{1,4}:){7}[[:xdigit:]]{1,4}\b\]?|` +
+		`\[?\b([[:xdigit:]]{1,4}:)+:([[:xdigit:]]{1,4}(:[[:xdigit:]]{1,4})*)?\b\]?`
+	hexRegexp    = `\b(0x[[:xdigit:]]+|[[:xdigit:]]{8,})\b`
+	numberRegexp = `\b\d+(\.\d+)?`
+)
+
+// templateMask replaces matches of a regular expression by a placeholder
+type templateMask struct {
+	rgx         *regexp.Regexp
+	replacement string
+}
+
+// templateMasks are applied in turn, so matches of more specific patterns are not split by the general ones
+var templateMasks = []templateMask{
+	{regexp.MustCompile(leadingContextRegexp), ""},
+	{regexp.MustCompile(quotedRegexp), "<str>"},
+	{regexp.MustCompile(uuidTokenRegexp), "<uuid>"},
+	{regexp.MustCompile(ipv4TokenRegexp), "<ip>"},
+	{regexp.MustCompile(ipv6TokenRegexp), "<ip>"},
+	{regexp.MustCompile(pathRegexp), "${1}<path>"},
+	{regexp.MustCompile(hexRegexp), "<hex>"},
+	{regexp.MustCompile(numberRegexp), "<num>"},
+}
+
+// getTemplate returns a message with variable parts masked, only the first line of a multiline message is used
+func getTemplate(msg string) string {
+	if i := strings.Index(msg, "\n"); i >= 0 {
+		msg = msg[:i]
+	}
+	for _, mask := range templateMasks {
+		msg = mask.rgx.ReplaceAllString(msg, mask.replacement)
+	}
+	return strings.TrimSpace(msg)
+}
+
+// getFingerprint returns tags `template` and `fingerprint`, which is a hash of the template, logger
+// and python module, so it identifies occurrences of the same event
+func getFingerprint(msg string, tags map[string]string) map[string]string {
+	template := getTemplate(msg)
+
+	hash := fnv.New64a()
+	for _, part := range []string{tags["logger"], tags["python_module"], template} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+
+	return map[string]string{
+		"template":    template,
+		"fingerprint": fmt.Sprintf("%016x", hash.Sum64()),
+	}
+}
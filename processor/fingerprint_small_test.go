@@ -0,0 +1,62 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetTemplate(t *testing.T) {
+	Convey("Get template of message", t, func() {
+		for msg, expected := range map[string]string{
+			"[req-0c0b761c-47b0-4bf5-832c-89ef048fa56a admin demo - - -] Instance 0c0b761c-47b0-4bf5-832c-89ef048fa56a failed to spawn": "Instance <uuid> failed to spawn",
+			"[-] Failed to connect to 10.0.0.1:5672 after 3 attempts, retrying in 2.5 seconds":                                          "Failed to connect to <ip> after <num> attempts, retrying in <num> seconds",
+			"Binding of port b1ad1df9062a4fc682904c6c9b0f4e98 on fe80::1 failed":                                                        "Binding of port <uuid> on <ip> failed",
+			"Lock \"compute_resources\" acquired by 'nova.compute.claim' :: waited 0.000s":                                              "Lock <str> acquired by <str> :: waited <num>s",
+			"Unable to read /var/lib/nova/instances/_base/3f8a1c2b, device 0x7f3a9c":                                                    "Unable to read <path>, device <hex>",
+			"Unexpected exception in API method\nTraceback (most recent call last):":                                                    "Unexpected exception in API method",
+		} {
+			So(getTemplate(msg), ShouldEqual, expected)
+		}
+	})
+}
+
+func TestGetFingerprint(t *testing.T) {
+	Convey("Get fingerprint of message", t, func() {
+		tags := map[string]string{"logger": "openstack.nova", "python_module": "nova.compute.manager"}
+		fp1 := getFingerprint("Instance 0c0b761c-47b0-4bf5-832c-89ef048fa56a failed to spawn", tags)
+		fp2 := getFingerprint("Instance b571ba10-0b4e-4411-a233-3df02488eae1 failed to spawn", tags)
+
+		So(fp1["template"], ShouldEqual, "Instance <uuid> failed to spawn")
+		So(fp1["fingerprint"], ShouldHaveLength, 16)
+		Convey("the same event should have the same fingerprint", func() {
+			So(fp2, ShouldResemble, fp1)
+		})
+		Convey("the same event of other module should have other fingerprint", func() {
+			fp3 := getFingerprint("Instance 0c0b761c-47b0-4bf5-832c-89ef048fa56a failed to spawn",
+				map[string]string{"logger": "openstack.nova", "python_module": "nova.conductor.manager"})
+			So(fp3["template"], ShouldEqual, fp1["template"])
+			So(fp3["fingerprint"], ShouldNotEqual, fp1["fingerprint"])
+		})
+	})
+}
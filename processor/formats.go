@@ -0,0 +1,153 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// names of log formats, they are used in config item `log_formats`
+	openstackFormatName = "openstack"
+	apacheFormatName    = "apache"
+	rabbitmqFormatName  = "rabbitmq"
+	mariadbFormatName   = "mariadb"
+	syslogFormatName    = "syslog"
+	// autoFormatName selects auto-detection of log format
+	autoFormatName = "auto"
+)
+
+// logFormat is implemented by each supported format of logs, it parses a log with settings of the given parser
+// and returns log's timestamp, message and other fields stored as tags; startsRecord returns true if a line starts
+// a new log record, so batched logs are split into records (see batch.go)
+type logFormat interface {
+	parse(p *parser, data string) (time.Time, string, map[string]string, error)
+	startsRecord(line string) bool
+}
+
+// logFormats is a registry of supported log formats
+var logFormats = map[string]logFormat{
+	openstackFormatName: openstackFormat{},
+	apacheFormatName:    apacheFormat{},
+	rabbitmqFormatName:  rabbitmqFormat{},
+	mariadbFormatName:   mariadbFormat{},
+	syslogFormatName:    syslogFormat{},
+}
+
+// autoDetectedFormats lists formats tried in turn when a format is not selected for a log file, the more specific
+// format is the earlier it is tried, as the Openstack pattern matches also i.a. MariaDB logs
+var autoDetectedFormats = []string{
+	syslogFormatName,
+	rabbitmqFormatName,
+	mariadbFormatName,
+	apacheFormatName,
+	openstackFormatName,
+}
+
+// formatRule selects a log format for log files which names match the pattern
+type formatRule struct {
+	pattern string
+	format  string
+}
+
+// parseFormatRules returns rules parsed from value of config item `log_formats`, which has a form of comma separated
+// list of `<log_file_pattern>=<format>`, e.g. "rabbit*=rabbitmq,mysql*.log=mariadb"; patterns have syntax of path.Match
+func parseFormatRules(val string) ([]formatRule, error) {
+	rules := []formatRule{}
+	for _, item := range strings.Split(val, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		i := strings.LastIndex(item, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("missing log format in `%s`", item)
+		}
+		rule := formatRule{
+			pattern: strings.TrimSpace(item[:i]),
+			format:  strings.TrimSpace(item[i+1:]),
+		}
+		if _, err := path.Match(rule.pattern, ""); err != nil || rule.pattern == "" {
+			return nil, fmt.Errorf("invalid log file pattern in `%s`", item)
+		}
+		if _, exist := logFormats[rule.format]; !exist && rule.format != autoFormatName {
+			return nil, fmt.Errorf("unknown log format `%s`", rule.format)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// getFormat returns a name of log format selected for the log file by the first matching rule or `auto`
+func (p *parser) getFormat(logFile string) string {
+	for _, rule := range p.formatRules {
+		if matched, _ := path.Match(rule.pattern, logFile); matched {
+			return rule.format
+		}
+	}
+	return autoFormatName
+}
+
+// processLog parses a log in a format selected for the log file or, if none is selected, in the first format
+// which fits; when no format fits, the error of the last tried one, i.e. Openstack format, is returned
+func (p *parser) processLog(logFile string, data string) (timestamp time.Time, msg string, fields map[string]string, err error) {
+	format := p.getFormat(logFile)
+	if format != autoFormatName {
+		return logFormats[format].parse(p, data)
+	}
+
+	for _, format := range autoDetectedFormats {
+		timestamp, msg, fields, err = logFormats[format].parse(p, data)
+		if err == nil {
+			return
+		}
+	}
+	return
+}
+
+// openstackFormat is a format of logs written by oslo.log, either plain-text or JSON formatted
+type openstackFormat struct{}
+
+func (openstackFormat) parse(p *parser, data string) (time.Time, string, map[string]string, error) {
+	timestamp, msg, fields, err := p.processOpenstackLog(data)
+	if err != nil {
+		return timestamp, msg, fields, err
+	}
+
+	p.addRequestContexts(msg, fields)
+	return timestamp, msg, fields, nil
+}
+
+func (openstackFormat) startsRecord(line string) bool {
+	return recordStartRgx.MatchString(line) || isJSONLog(line)
+}
+
+// addRequestContexts adds a request context and an HTTP request context retrieved from msg to fields
+func (p *parser) addRequestContexts(msg string, fields map[string]string) {
+	if msg != "" {
+		// for not empty msg, do retrieving a request context unless it has been already retrieved from log
+		if _, exist := fields["request_id"]; !exist {
+			mergeMaps(fields, p.getRequestContext(msg))
+		}
+		mergeMaps(fields, p.getHTTPRequestContext(msg))
+	}
+}
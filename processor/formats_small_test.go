@@ -0,0 +1,91 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseFormatRules(t *testing.T) {
+	Convey("Parse rules selecting log formats", t, func() {
+		Convey("Valid rules are returned in order", func() {
+			rules, err := parseFormatRules("rabbit*=rabbitmq, mysqld.log=mariadb,*=auto")
+			So(err, ShouldBeNil)
+			So(rules, ShouldResemble, []formatRule{
+				{pattern: "rabbit*", format: "rabbitmq"},
+				{pattern: "mysqld.log", format: "mariadb"},
+				{pattern: "*", format: "auto"},
+			})
+		})
+		Convey("Invalid rules give an error", func() {
+			for _, val := range []string{"rabbit*", "=rabbitmq", "rabbit*=journald", "[=apache"} {
+				_, err := parseFormatRules(val)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+}
+
+func TestProcessLog(t *testing.T) {
+	Convey("Create logs-openstack processor", t, func() {
+		processor := New()
+		So(processor, ShouldNotBeNil)
+
+		logs := map[string]string{
+			openstackFormatName: "2016-12-07 03:26:24.254 7 INFO nova.console.websocketproxy [-] handler exception",
+			apacheFormatName:    `10.0.0.1 - admin [07/Dec/2016:03:26:24 +0000] "GET /v3 HTTP/1.1" 200 2326 "-" "curl/7.29.0"`,
+			rabbitmqFormatName:  "2016-12-07 03:26:24.254 [info] <0.197.0> Starting RabbitMQ 3.7.4 on Erlang 20.2",
+			mariadbFormatName:   "2016-12-07  3:26:24 140234567890 [Note] WSREP: Synchronized with group, ready for connections",
+		}
+
+		Convey("format of log should be auto-detected", func() {
+			// each format produces a tag specific to it
+			tags := map[string]string{
+				openstackFormatName: "python_module",
+				apacheFormatName:    "http_status",
+				rabbitmqFormatName:  "erlang_pid",
+				mariadbFormatName:   "thread_id",
+			}
+			for format, data := range logs {
+				_, _, fields, err := processor.processLog("mock.log", data)
+				So(err, ShouldBeNil)
+				So(fields, ShouldContainKey, tags[format])
+			}
+		})
+		Convey("format selected for log file should be used", func() {
+			prs, err := processor.parser.configure(map[string]string{logFormatsConfig: "rabbit*=rabbitmq"})
+			So(err, ShouldBeNil)
+			So(prs.getFormat("rabbit@controller.log"), ShouldEqual, rabbitmqFormatName)
+			So(prs.getFormat("nova-api.log"), ShouldEqual, autoFormatName)
+
+			_, _, _, err = prs.processLog("rabbit@controller.log", logs[rabbitmqFormatName])
+			So(err, ShouldBeNil)
+			_, _, _, err = prs.processLog("rabbit@controller.log", logs[openstackFormatName])
+			So(err, ShouldNotBeNil)
+		})
+		Convey("error should be returned when no format fits", func() {
+			_, _, _, err := processor.processLog("mock.log", "invalid")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
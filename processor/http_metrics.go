@@ -0,0 +1,101 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"strconv"
+
+	"github.com/intelsdi-x/snap-plugin-lib-go/v1/plugin"
+	log "github.com/sirupsen/logrus"
+)
+
+// httpMetric describes a numeric metric derived from a tag of HTTP request context
+type httpMetric struct {
+	name        string
+	tag         string
+	unit        string
+	description string
+	// convert returns a numeric value of the tag
+	convert func(string) (interface{}, error)
+}
+
+// httpMetrics lists numeric metrics which are derived from HTTP request context,
+// they are emitted as /intel/logs/openstack/<service_name>/http/<name>
+var httpMetrics = []httpMetric{
+	{
+		name:        "response_time",
+		tag:         "http_response_time",
+		unit:        "s",
+		description: "Time of processing HTTP request",
+		convert: func(val string) (interface{}, error) {
+			return strconv.ParseFloat(val, 64)
+		},
+	},
+	{
+		name:        "response_size",
+		tag:         "http_response_size",
+		unit:        "B",
+		description: "Size of HTTP response",
+		convert: func(val string) (interface{}, error) {
+			return strconv.ParseInt(val, 10, 64)
+		},
+	},
+}
+
+// getHTTPMetrics returns numeric metrics derived from HTTP request context of processed metric
+// or nil when there is no HTTP request context in its tags
+func getHTTPMetrics(m plugin.Metric) []plugin.Metric {
+	serviceName := getService(m)
+	if serviceName == "" {
+		return nil
+	}
+
+	var derived []plugin.Metric
+	for _, hm := range httpMetrics {
+		val, exist := m.Tags[hm.tag]
+		if !exist {
+			continue
+		}
+		data, err := hm.convert(val)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"_block": "getHTTPMetrics",
+				"_tag":   hm.tag,
+				"_value": val,
+				"_error": err,
+			}).Warning("Cannot convert HTTP request context to numeric value")
+			continue
+		}
+
+		tags := make(map[string]string, len(m.Tags))
+		mergeMaps(tags, m.Tags)
+
+		derived = append(derived, plugin.Metric{
+			Namespace:   plugin.NewNamespace("intel", "logs", "openstack", serviceName, "http", hm.name),
+			Version:     m.Version,
+			Config:      m.Config,
+			Data:        data,
+			Tags:        tags,
+			Timestamp:   m.Timestamp,
+			Unit:        hm.unit,
+			Description: hm.description,
+		})
+	}
+	return derived
+}
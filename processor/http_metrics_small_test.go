@@ -0,0 +1,59 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetHTTPMetrics(t *testing.T) {
+	Convey("Get numeric metrics derived from HTTP request context", t, func() {
+		mt := createMockMetric("nova-api.log", "some_message")
+		mt.Tags["logger"] = "openstack.nova"
+
+		Convey("should return nil when there is no HTTP request context", func() {
+			So(getHTTPMetrics(mt), ShouldBeNil)
+		})
+		Convey("should return response time and size", func() {
+			mt.Tags["http_response_time"] = "0.4711170"
+			mt.Tags["http_response_size"] = "23011"
+			derived := getHTTPMetrics(mt)
+			So(derived, ShouldHaveLength, 2)
+			So(derived[0].Namespace.Strings(), ShouldResemble, []string{"intel", "logs", "openstack", "nova", "http", "response_time"})
+			So(derived[0].Data, ShouldEqual, float64(0.4711170))
+			So(derived[1].Namespace.Strings(), ShouldResemble, []string{"intel", "logs", "openstack", "nova", "http", "response_size"})
+			So(derived[1].Data, ShouldEqual, int64(23011))
+			Convey("with tags and timestamp of the log metric", func() {
+				So(derived[0].Timestamp, ShouldResemble, mt.Timestamp)
+				So(derived[0].Tags, ShouldResemble, mt.Tags)
+			})
+		})
+		Convey("should skip values which are not numeric", func() {
+			mt.Tags["http_response_time"] = "0.47.11"
+			mt.Tags["http_response_size"] = "23011"
+			derived := getHTTPMetrics(mt)
+			So(derived, ShouldHaveLength, 1)
+			So(derived[0].Data, ShouldEqual, int64(23011))
+		})
+	})
+}
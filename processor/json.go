@@ -0,0 +1,131 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ***	JSON LOG FORMAT   ***
+// 	Openstack services configured with `oslo_log.formatters.JSONFormatter` log one JSON object per line, i.a.
+//
+// 	{"message": "some_message", "asctime": "2016-12-08 03:18:49,626", "created": 1481167129.626, "process": 20,
+// 	 "levelname": "ERROR", "name": "nova.api.openstack.extensions", "traceback": null,
+// 	 "context": {"request_id": "req-0c0b761c-47b0-4bf5-832c-89ef048fa56a", "user": "fa2b2986c200431b8119035d4a47d420",
+// 	             "project_id": "b1ad1df9062a4fc682904c6c9b0f4e98"}}
+//
+// 	Its fields are mapped into the same fields as the ones retrieved from the plain-text log:
+// 	`process` -> `pid`, `levelname` -> `severity_label`, `name` -> `python_module`, `message` -> payload,
+// 	`context.request_id` -> `request_id`, `context.user` -> `user_id`, `context.project_id` -> `tenant_id`
+// 	(or `user_name` and `project_name` when they are names instead of ids)
+
+// jsonLog holds fields of a log formatted by oslo_log.formatters.JSONFormatter which are processed
+type jsonLog struct {
+	Message   *string                `json:"message"`
+	Asctime   string                 `json:"asctime"`
+	Created   float64                `json:"created"`
+	Process   json.Number            `json:"process"`
+	Levelname string                 `json:"levelname"`
+	Name      string                 `json:"name"`
+	Traceback []string               `json:"traceback"`
+	Context   map[string]interface{} `json:"context"`
+}
+
+// jsonContextFields maps fields of the tag set onto keys of the JSON log context, the first one found is used
+var jsonContextFields = map[string][]string{
+	"request_id": {"request_id"},
+	"user_id":    {"user", "user_id"},
+	"tenant_id":  {"project_id", "tenant", "project"},
+}
+
+// isJSONLog returns true if data looks like a log formatted as a JSON object
+func isJSONLog(data string) bool {
+	data = strings.TrimSpace(data)
+	return strings.HasPrefix(data, "{") && strings.HasSuffix(data, "}")
+}
+
+// processJSONLog processes incoming openstack log formatted as a JSON object and retrieves such info like
+// log's timestamp, message and others fields the same as processOpenstackLog does
+func (p *parser) processJSONLog(data string) (timestamp time.Time, msg string, fields map[string]string, err error) {
+	entry := jsonLog{}
+	if err = json.Unmarshal([]byte(data), &entry); err != nil {
+		return
+	}
+
+	// set a timestamp, the epoch time is preferred as it does not depend on timezone
+	switch {
+	case entry.Created > 0:
+		sec, frac := math.Modf(entry.Created)
+		// round to microseconds as the epoch time is a float
+		timestamp = time.Unix(int64(sec), int64(math.Floor(frac*1e6+0.5))*1e3).In(p.location)
+	case entry.Asctime != "":
+		timestamp, err = parseTimestamp(entry.Asctime, p.location)
+		if err != nil {
+			return
+		}
+	default:
+		err = fmt.Errorf("No timestamp in log")
+		return
+	}
+
+	// set a msg which corresponds to `message` followed by lines of traceback
+	if entry.Message == nil {
+		err = fmt.Errorf("No payload in log")
+		return
+	}
+	msg = *entry.Message
+	if len(entry.Traceback) > 0 {
+		msg = strings.Join(append([]string{msg}, entry.Traceback...), "\n")
+	}
+
+	fields = map[string]string{}
+	if entry.Process != "" {
+		fields["pid"] = entry.Process.String()
+	}
+	if entry.Levelname != "" {
+		fields["severity_label"] = entry.Levelname
+	}
+	if entry.Name != "" {
+		fields["python_module"] = entry.Name
+	}
+	for field, keys := range jsonContextFields {
+		for _, key := range keys {
+			if val, ok := entry.Context[key].(string); ok && val != "" {
+				fields[field] = val
+				break
+			}
+		}
+	}
+	// keep request_id without `req-` prefix as it is retrieved from the plain-text log
+	if requestID, ok := fields["request_id"]; ok {
+		fields["request_id"] = strings.TrimPrefix(requestID, "req-")
+	}
+	// user and project might be identified by names as in the plain-text log
+	setContextNames(fields)
+
+	if err = p.setSeverity(fields); err != nil {
+		return
+	}
+
+	return timestamp, msg, fields, nil
+}
@@ -0,0 +1,91 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProcessJSONLog(t *testing.T) {
+	Convey("Create logs-openstack processor", t, func() {
+		processor := New()
+		So(processor, ShouldNotBeNil)
+
+		Convey("Process JSON log unsuccessfully", func() {
+			Convey("should return an error when log is not valid JSON", func() {
+				_, _, _, err := processor.processOpenstackLog(`{"message": "some_message"`)
+				So(err, ShouldNotBeNil)
+			})
+			Convey("should return an error when there is no timestamp in log", func() {
+				_, _, _, err := processor.processOpenstackLog(`{"message": "some_message", "levelname": "INFO"}`)
+				So(err, ShouldNotBeNil)
+			})
+			Convey("should return an error when there is no message in log", func() {
+				_, _, _, err := processor.processOpenstackLog(`{"created": 1481167129.626, "levelname": "INFO"}`)
+				So(err, ShouldNotBeNil)
+			})
+		})
+		Convey("Process JSON log successfully", func() {
+			Convey("with a request context", func() {
+				timestamp, msg, fields, err := processor.processOpenstackLog(`{"message": "Unexpected exception in API method", ` +
+					`"asctime": "2016-12-08 03:18:49,626", "created": 1481167129.626, "process": 20, "levelname": "ERROR", ` +
+					`"name": "nova.api.openstack.extensions", "traceback": null, "context": {"request_id": "req-0c0b761c-47b0-4bf5-832c-89ef048fa56a", ` +
+					`"user": "fa2b2986c200431b8119035d4a47d420", "project_id": "b1ad1df9062a4fc682904c6c9b0f4e98", "is_admin": true}}`)
+				So(err, ShouldBeNil)
+				So(timestamp.UTC(), ShouldResemble, time.Date(2016, 12, 8, 3, 18, 49, 626000000, time.UTC))
+				So(msg, ShouldEqual, "Unexpected exception in API method")
+				So(fields, ShouldResemble, map[string]string{
+					"pid":            "20",
+					"severity_label": "ERROR",
+					"severity":       "3",
+					"python_module":  "nova.api.openstack.extensions",
+					"request_id":     "0c0b761c-47b0-4bf5-832c-89ef048fa56a",
+					"user_id":        "fa2b2986c200431b8119035d4a47d420",
+					"tenant_id":      "b1ad1df9062a4fc682904c6c9b0f4e98",
+				})
+			})
+			Convey("with a request context of user and project names", func() {
+				_, _, fields, err := processor.processOpenstackLog(`{"message": "Stopping WSGI server.", "created": 1481167129.626, ` +
+					`"context": {"request_id": "req-0c0b761c-47b0-4bf5-832c-89ef048fa56a", "user": "admin", "project_id": "demo"}}`)
+				So(err, ShouldBeNil)
+				So(fields, ShouldResemble, map[string]string{
+					"request_id":   "0c0b761c-47b0-4bf5-832c-89ef048fa56a",
+					"user_name":    "admin",
+					"project_name": "demo",
+				})
+			})
+			Convey("with timestamp only in asctime", func() {
+				timestamp, _, _, err := processor.processOpenstackLog(`{"message": "Stopping WSGI server.", "asctime": "2016-12-08 03:18:49,626"}`)
+				So(err, ShouldBeNil)
+				So(timestamp, ShouldResemble, time.Date(2016, 12, 8, 3, 18, 49, 626000000, time.Local))
+			})
+			Convey("with a traceback", func() {
+				_, msg, _, err := processor.processOpenstackLog(`{"message": "Unexpected exception", "created": 1481167129.626, ` +
+					`"traceback": ["Traceback (most recent call last):", "  File \"a.py\", line 1, in <module>", "ValueError: invalid literal"]}`)
+				So(err, ShouldBeNil)
+				So(msg, ShouldEqual, "Unexpected exception\nTraceback (most recent call last):\n  File \"a.py\", line 1, in <module>\nValueError: invalid literal")
+			})
+		})
+	})
+}
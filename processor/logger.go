@@ -0,0 +1,160 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/intelsdi-x/snap-plugin-lib-go/v1/plugin"
+)
+
+const (
+	// ***	PATTERN FOR LOGGER   ***
+	// 	Logger is built from a value of namespace element, by default the `log_file` provided by snap-plugin-collector-logs,
+	// 	which might be preceded by a directory (i.a. `nova/nova-compute.log` on Kolla deployments), where:
+	// 	- `service` is the first part of the name split by the '-' separator, i.a. `neutron`
+	// 	- `component` is the full name without the `.log` suffix, i.a. `neutron-openvswitch-agent`
+	defaultLoggerElement = "log_file"
+	loggerRegexp         = `^(.*/)?(?P<component>(?P<service>[^/-]+?)(-[^/]*?)?)(\.log)?$`
+
+	// defaultHostnameElement is a name of optional dynamic element of metric's namespace holding a hostname
+	defaultHostnameElement = "hostname"
+
+	// defaultLoggerTemplate is a template of logger where `{<group_name>}` is replaced by a value of named group
+	defaultLoggerTemplate = "openstack.{service}"
+
+	// ***	PATTERN FOR KUBERNETES LOG FILES   ***
+	// 	Container runtimes on Kubernetes nodes (i.a. with openstack-helm) write logs to files named in a form of
+	// 	`/var/log/containers/<pod>_<namespace>_<container>-<container_id>.log`, in `kubernetes` naming of log files
+	// 	these parts are stored as tags and the logger is retrieved from the container name, i.a. `nova-api`
+	kubernetesLogFileRegexp = `^(.*/)?(?P<k8s_pod>[^/_]+)_(?P<k8s_namespace>[^/_]+)_(?P<k8s_container>[^/_]+)-(?P<container_id>[0-9a-f]{64})\.log$`
+
+	// namings of log files, they are values of config item `log_file_naming`
+	logFileNamingDefault    = "default"
+	logFileNamingKubernetes = "kubernetes"
+)
+
+// loggerTemplateRgx matches placeholders of named groups in a logger template
+var loggerTemplateRgx = regexp.MustCompile(`\{(\w+)\}`)
+
+// kubernetesLogFileRgx matches names of log files written on Kubernetes nodes
+var kubernetesLogFileRgx = regexp.MustCompile(kubernetesLogFileRegexp)
+
+// kubernetesTags lists tags retrieved from names of log files written on Kubernetes nodes
+var kubernetesTags = []string{"k8s_pod", "k8s_namespace", "k8s_container", "container_id"}
+
+// getLoggerInfo returns tags describing a logger, i.e. `logger`, `service`, `component`, `hostname` and Kubernetes metadata
+// (if they occur),
+// retrieved from the last dynamic element of metric's namespace with the parser's logger element name,
+// and value of that element which is used as the log file name
+func (p *parser) getLoggerInfo(ns plugin.Namespace) (tags map[string]string, logFile string, err error) {
+	isDynamic, indexes := ns.IsDynamic()
+	if !isDynamic {
+		return nil, "", fmt.Errorf("Metric `%v` is expected to contain a dynamic element, but it doesn't", ns.Strings())
+	}
+
+	found := false
+	for i := len(indexes) - 1; i >= 0; i-- {
+		if element := ns.Element(indexes[i]); element.Name == p.loggerElement {
+			logFile = element.Value
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, "", fmt.Errorf("Metric `%v` is expected to contain a dynamic element `%s`, but it doesn't", ns.Strings(), p.loggerElement)
+	}
+
+	tags = map[string]string{}
+	loggerName := logFile
+	if p.logFileNaming == logFileNamingKubernetes {
+		// the logger is retrieved from the container name, files named in other way are handled as by default
+		if k8sFields, err := parse(logFile, kubernetesLogFileRgx); err == nil {
+			for _, name := range kubernetesTags {
+				tags[name] = k8sFields[name]
+			}
+			loggerName = k8sFields["k8s_container"]
+		}
+	}
+
+	fields, err := parse(loggerName, p.loggerRgx)
+	if err != nil || fields["service"] == "" {
+		return nil, "", fmt.Errorf("Cannot retrieve service from `%s` of metric `%v`", logFile, ns.Strings())
+	}
+
+	tags["service"] = fields["service"]
+	if component, exist := fields["component"]; exist {
+		tags["component"] = component
+	}
+	tags["logger"] = formatLogger(p.loggerTemplate, fields)
+
+	if hostname := p.getHostname(ns); hostname != "" {
+		tags["hostname"] = hostname
+	}
+
+	return tags, logFile, nil
+}
+
+// checkLogFileNaming returns the naming of log files or an error if it is invalid
+func checkLogFileNaming(naming string) (string, error) {
+	naming = strings.ToLower(strings.TrimSpace(naming))
+	switch naming {
+	case logFileNamingDefault, logFileNamingKubernetes:
+		return naming, nil
+	}
+	return "", fmt.Errorf("`%s` is not one of %v", naming, []string{logFileNamingDefault, logFileNamingKubernetes})
+}
+
+// getHostname returns a value of the hostname element of metric's namespace or, if there is no such element,
+// the hostname set in the parser's config
+func (p *parser) getHostname(ns plugin.Namespace) string {
+	_, indexes := ns.IsDynamic()
+	for _, i := range indexes {
+		if element := ns.Element(i); element.Name == p.hostnameElement && element.Value != "" {
+			return element.Value
+		}
+	}
+	return p.hostname
+}
+
+// formatLogger returns a logger built from the template, placeholders of groups which were not captured are removed
+// together with dots they leave
+func formatLogger(template string, fields map[string]string) string {
+	logger := loggerTemplateRgx.ReplaceAllStringFunc(template, func(placeholder string) string {
+		return fields[strings.Trim(placeholder, "{}")]
+	})
+
+	parts := []string{}
+	for _, part := range strings.Split(logger, ".") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// getService returns a name of Openstack service which logged a processed metric
+func getService(m plugin.Metric) string {
+	if service, exist := m.Tags["service"]; exist {
+		return service
+	}
+	return strings.TrimPrefix(m.Tags["logger"], "openstack.")
+}
@@ -0,0 +1,155 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"testing"
+
+	"github.com/intelsdi-x/snap-plugin-lib-go/v1/plugin"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetLoggerInfo(t *testing.T) {
+	Convey("Create logs-openstack processor", t, func() {
+		processor := New()
+		So(processor, ShouldNotBeNil)
+
+		Convey("logger, service and component should be retrieved from log file", func() {
+			for logFile, expected := range map[string]map[string]string{
+				"nova-api.log":                  {"logger": "openstack.nova", "service": "nova", "component": "nova-api"},
+				"neutron-openvswitch-agent.log": {"logger": "openstack.neutron", "service": "neutron", "component": "neutron-openvswitch-agent"},
+				"keystone.log":                  {"logger": "openstack.keystone", "service": "keystone", "component": "keystone"},
+				"nova/nova-compute.log":         {"logger": "openstack.nova", "service": "nova", "component": "nova-compute"},
+			} {
+				tags, file, err := processor.getLoggerInfo(createMockMetric(logFile, "").Namespace)
+				So(err, ShouldBeNil)
+				So(file, ShouldEqual, logFile)
+				So(tags, ShouldResemble, expected)
+			}
+		})
+		Convey("configured element, regular expression and template should be used", func() {
+			prs, err := processor.parser.configure(map[string]string{
+				loggerElementConfig:  "metric_name",
+				loggerRegexpConfig:   `^(?P<service>[a-z]+)(_(?P<component>[a-z]+))?$`,
+				loggerTemplateConfig: "{service}.{component}",
+			})
+			So(err, ShouldBeNil)
+			tags, file, err := prs.getLoggerInfo(createMockMetric("nova-api.log", "").Namespace)
+			So(err, ShouldBeNil)
+			So(file, ShouldEqual, "mock")
+			So(tags, ShouldResemble, map[string]string{"logger": "mock", "service": "mock"})
+		})
+		Convey("an error should be returned when element does not exist", func() {
+			prs, err := processor.parser.configure(map[string]string{loggerElementConfig: "service"})
+			So(err, ShouldBeNil)
+			_, _, err = prs.getLoggerInfo(createMockMetric("nova-api.log", "").Namespace)
+			So(err, ShouldNotBeNil)
+		})
+		Convey("an error should be returned when service cannot be retrieved", func() {
+			prs, err := processor.parser.configure(map[string]string{loggerRegexpConfig: `^(?P<service>[a-z]+)\.log$`})
+			So(err, ShouldBeNil)
+			_, _, err = prs.getLoggerInfo(createMockMetric("nova-api.log", "").Namespace)
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Kubernetes metadata should be retrieved in kubernetes naming of log files", func() {
+			containerID := "5f3a5b1c8e2d4f6a9b0c1d2e3f4a5b6c7d8e9f0a1b2c3d4e5f6a7b8c9d0e1f2a"
+			logFile := "/var/log/containers/nova-api-osapi-7f8d9c6b5-x2k4p_openstack_nova-osapi-" + containerID + ".log"
+			prs, err := processor.parser.configure(map[string]string{logFileNamingConfig: "kubernetes"})
+			So(err, ShouldBeNil)
+			tags, file, err := prs.getLoggerInfo(createMockMetric(logFile, "").Namespace)
+			So(err, ShouldBeNil)
+			So(file, ShouldEqual, logFile)
+			So(tags, ShouldResemble, map[string]string{
+				"logger":        "openstack.nova",
+				"service":       "nova",
+				"component":     "nova-osapi",
+				"k8s_pod":       "nova-api-osapi-7f8d9c6b5-x2k4p",
+				"k8s_namespace": "openstack",
+				"k8s_container": "nova-osapi",
+				"container_id":  containerID,
+			})
+
+			Convey("and log files named in other way should be handled as by default", func() {
+				tags, _, err := prs.getLoggerInfo(createMockMetric("nova/nova-compute.log", "").Namespace)
+				So(err, ShouldBeNil)
+				So(tags, ShouldResemble, map[string]string{"logger": "openstack.nova", "service": "nova", "component": "nova-compute"})
+			})
+			Convey("but not in default naming of log files", func() {
+				tags, _, err := processor.getLoggerInfo(createMockMetric(logFile, "").Namespace)
+				So(err, ShouldBeNil)
+				So(tags, ShouldNotContainKey, "k8s_pod")
+			})
+		})
+		Convey("unknown naming of log files should be invalid", func() {
+			_, err := processor.parser.configure(map[string]string{logFileNamingConfig: "docker"})
+			So(err, ShouldNotBeNil)
+		})
+		Convey("regular expression without service should be invalid", func() {
+			_, err := processor.parser.configure(map[string]string{loggerRegexpConfig: `^(?P<component>.+)$`})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestFormatLogger(t *testing.T) {
+	Convey("Format logger from template", t, func() {
+		fields := map[string]string{"service": "neutron", "component": "neutron-l3-agent"}
+		So(formatLogger("openstack.{service}", fields), ShouldEqual, "openstack.neutron")
+		So(formatLogger("{service}.{component}", fields), ShouldEqual, "neutron.neutron-l3-agent")
+		So(formatLogger("openstack.{service}.{host}", fields), ShouldEqual, "openstack.neutron")
+	})
+}
+
+func TestGetHostname(t *testing.T) {
+	Convey("Create logs-openstack processor", t, func() {
+		processor := New()
+		So(processor, ShouldNotBeNil)
+
+		ns := plugin.NewNamespace("intel", "logs").
+			AddDynamicElement("hostname", "Host name").
+			AddDynamicElement("log_file", "Log file name").AddStaticElement("metric")
+		ns[2].Value = "compute-1"
+		ns[3].Value = "nova-compute.log"
+
+		Convey("hostname should be retrieved from namespace", func() {
+			tags, _, err := processor.getLoggerInfo(ns)
+			So(err, ShouldBeNil)
+			So(tags["hostname"], ShouldEqual, "compute-1")
+			So(tags["component"], ShouldEqual, "nova-compute")
+		})
+		Convey("hostname should not be set when it is unknown", func() {
+			tags, _, err := processor.getLoggerInfo(createMockMetric("nova-compute.log", "").Namespace)
+			So(err, ShouldBeNil)
+			So(tags, ShouldNotContainKey, "hostname")
+		})
+		Convey("configured hostname should be used when namespace does not hold it", func() {
+			prs, err := processor.parser.configure(map[string]string{hostnameConfig: "controller-1"})
+			So(err, ShouldBeNil)
+			So(prs.getHostname(createMockMetric("nova-compute.log", "").Namespace), ShouldEqual, "controller-1")
+			So(prs.getHostname(ns), ShouldEqual, "compute-1")
+		})
+		Convey("configured hostname element should be used", func() {
+			prs, err := processor.parser.configure(map[string]string{hostnameElementConfig: "metric_name"})
+			So(err, ShouldBeNil)
+			So(prs.getHostname(createMockMetric("nova-compute.log", "").Namespace), ShouldEqual, "mock")
+		})
+	})
+}
@@ -0,0 +1,104 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// ***	PATTERN FOR MARIADB LOG   ***
+	// 	MariaDB and MySQL (also with Galera) log messages in the following form, where `thread_id` might not occur:
+	// 	<timestamp> <thread_id> [<severity_label>] <payload>
+	//
+	// 	Examples:	2016-12-07  3:26:24 140234567890 [Note] WSREP: Synchronized with group, ready for connections
+	// 			161207  3:26:24 [Note] InnoDB: Completed initialization of buffer pool
+	// 			2016-12-07T03:26:24.254123Z 0 [Warning] [MY-010068] [Server] CA certificate ca.pem is self signed.
+	//
+	// 	**Notice** that MySQL 8 adds `error_code` and `subsystem` after the severity label
+	// 	**Notice** that severity labels are capitalized, unlike the ones of RabbitMQ logs which have a similar form
+	mariadbLogRegexp = `^(?P<timestamp>\d{4}-\d{2}-\d{2}(T| {1,2})\d{1,2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})?|\d{6} {1,2}\d{1,2}:\d{2}:\d{2}) +` +
+		`((?P<thread_id>\d+) )?\[(?P<severity_label>[A-Z][A-Za-z]*)\]( \[(?P<error_code>MY-\d+)\] \[(?P<subsystem>\w+)\])? (?P<payload>(\n|.)*)`
+
+	// mariadbRecordStartRegexp matches a beginning of log, i.e. the timestamp, the thread id and the severity label
+	mariadbRecordStartRegexp = `^(\d{4}-\d{2}-\d{2}(T| {1,2})\d{1,2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})?|\d{6} {1,2}\d{1,2}:\d{2}:\d{2}) +` +
+		`(\d+ )?\[[A-Z][A-Za-z]*\]`
+
+	mariadbShortDateFormat = "060102"
+	mariadbDateFormat      = "2006-01-02"
+	mariadbClockFormat     = "15:04:05"
+)
+
+var (
+	mariadbLogRgx         = regexp.MustCompile(mariadbLogRegexp)
+	mariadbRecordStartRgx = regexp.MustCompile(mariadbRecordStartRegexp)
+)
+
+// mariadbFormat is a format of MariaDB and MySQL error logs
+type mariadbFormat struct{}
+
+func (mariadbFormat) parse(p *parser, data string) (timestamp time.Time, msg string, fields map[string]string, err error) {
+	fields, err = parse(data, mariadbLogRgx)
+	if err != nil {
+		return
+	}
+
+	timestamp, err = parseMariadbTimestamp(fields["timestamp"], p.location)
+	if err != nil {
+		return
+	}
+	delete(fields, "timestamp")
+
+	msg = fields["payload"]
+	delete(fields, "payload")
+
+	if err = p.setSeverity(fields); err != nil {
+		return
+	}
+	return timestamp, msg, fields, nil
+}
+
+func (mariadbFormat) startsRecord(line string) bool {
+	return mariadbRecordStartRgx.MatchString(line)
+}
+
+// parseMariadbTimestamp parses a timestamp written by MariaDB or MySQL, the one with UTC designator or offset
+// does not depend on the location
+func parseMariadbTimestamp(ts string, location *time.Location) (time.Time, error) {
+	if strings.Contains(ts, "T") {
+		if strings.HasSuffix(ts, "Z") || strings.ContainsAny(ts[len(mariadbDateFormat):], "+-") {
+			return time.Parse(time.RFC3339Nano, ts)
+		}
+		return time.ParseInLocation(mariadbDateFormat+"T"+mariadbClockFormat, ts, location)
+	}
+
+	// date and clock might be separated by two spaces, when hour is not zero-padded
+	parts := strings.Fields(ts)
+	date, clock := parts[0], parts[1]
+	if len(clock) < len(mariadbClockFormat) {
+		clock = "0" + clock
+	}
+	layout := mariadbDateFormat
+	if len(date) == len(mariadbShortDateFormat) {
+		layout = mariadbShortDateFormat
+	}
+	return time.ParseInLocation(layout+" "+mariadbClockFormat, date+" "+clock, location)
+}
@@ -0,0 +1,76 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMariadbFormat(t *testing.T) {
+	Convey("Create logs-openstack processor", t, func() {
+		processor := New()
+		So(processor, ShouldNotBeNil)
+		format := mariadbFormat{}
+
+		Convey("log of MariaDB should be parsed", func() {
+			timestamp, msg, fields, err := format.parse(processor.parser, "2016-12-07  3:26:24 140234567890 [Note] WSREP: Synchronized with group")
+			So(err, ShouldBeNil)
+			So(timestamp, ShouldResemble, time.Date(2016, 12, 7, 3, 26, 24, 0, time.Local))
+			So(msg, ShouldEqual, "WSREP: Synchronized with group")
+			So(fields, ShouldResemble, map[string]string{
+				"severity_label": "Note",
+				"severity":       "6",
+				"thread_id":      "140234567890",
+			})
+		})
+		Convey("log of older releases should be parsed", func() {
+			timestamp, msg, fields, err := format.parse(processor.parser, "161207 13:26:24 [ERROR] Aborting")
+			So(err, ShouldBeNil)
+			So(timestamp, ShouldResemble, time.Date(2016, 12, 7, 13, 26, 24, 0, time.Local))
+			So(msg, ShouldEqual, "Aborting")
+			So(fields, ShouldResemble, map[string]string{
+				"severity_label": "ERROR",
+				"severity":       "3",
+			})
+		})
+		Convey("log of MySQL 8 should be parsed", func() {
+			timestamp, msg, fields, err := format.parse(processor.parser,
+				"2016-12-07T03:26:24.254123Z 0 [Warning] [MY-010068] [Server] CA certificate ca.pem is self signed.")
+			So(err, ShouldBeNil)
+			So(timestamp, ShouldResemble, time.Date(2016, 12, 7, 3, 26, 24, 254123000, time.UTC))
+			So(msg, ShouldEqual, "CA certificate ca.pem is self signed.")
+			So(fields, ShouldResemble, map[string]string{
+				"severity_label": "Warning",
+				"severity":       "4",
+				"thread_id":      "0",
+				"error_code":     "MY-010068",
+				"subsystem":      "Server",
+			})
+		})
+		Convey("log in other format should give an error", func() {
+			_, _, _, err := format.parse(processor.parser, "2016-12-07 03:26:24.254 7 INFO nova.console.websocketproxy [-] handler exception")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,45 @@
+// +build small medium
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"time"
+
+	"github.com/intelsdi-x/snap-plugin-lib-go/v1/plugin"
+)
+
+func createMockMetric(logFileName string, logData string) plugin.Metric {
+	// see snap-plugin-collector-logs to find how metric's namespace is defined
+	ns := plugin.NewNamespace("intel", "logs").
+		AddDynamicElement("metric_name", "Metric name defined in config file").
+		AddDynamicElement("log_file", "Log file name").AddStaticElement("metric")
+
+	// set values to dynamic element
+	ns[2].Value = "mock"
+	ns[3].Value = logFileName
+
+	return plugin.Metric{
+		Namespace: ns,
+		Data:      logData,
+		Timestamp: time.Now(),
+		Tags:      map[string]string{},
+	}
+}
@@ -0,0 +1,67 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"fmt"
+
+	"github.com/intelsdi-x/snap-plugin-lib-go/v1/plugin"
+)
+
+const (
+	// modes of handling metrics which cannot be processed, set by config item `on_parse_error`
+	parseErrorPassthrough = "passthrough"
+	parseErrorDrop        = "drop"
+	parseErrorTag         = "tag"
+
+	// stages of processing at which a metric might fail, they are set as `parse_stage` tag
+	parseStageLoggerInfo = "logger_info"
+	parseStageDataType   = "data_type"
+	parseStageLogFormat  = "log_format"
+)
+
+// parseErrorModes lists valid values of config item `on_parse_error`
+var parseErrorModes = []string{parseErrorPassthrough, parseErrorDrop, parseErrorTag}
+
+// handleParseError returns metrics which should be emitted in place of a metric failed at the given stage:
+// the untouched metric (passthrough), nothing (drop) or the metric tagged with the error and the stage (tag)
+func handleParseError(m plugin.Metric, stage string, err error, mode string) []plugin.Metric {
+	switch mode {
+	case parseErrorDrop:
+		return nil
+	case parseErrorTag:
+		// tags are copied, so the incoming metric is not modified
+		tags := make(map[string]string, len(m.Tags)+2)
+		mergeMaps(tags, m.Tags)
+		tags["parse_error"] = err.Error()
+		tags["parse_stage"] = stage
+		m.Tags = tags
+	}
+	return []plugin.Metric{m}
+}
+
+// checkParseErrorMode returns an error if mode is not a valid value of config item `on_parse_error`
+func checkParseErrorMode(mode string) error {
+	for _, valid := range parseErrorModes {
+		if mode == valid {
+			return nil
+		}
+	}
+	return fmt.Errorf("Invalid value of config item `%s`: `%s` is not one of %v", onParseErrorConfig, mode, parseErrorModes)
+}
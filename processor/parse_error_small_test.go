@@ -0,0 +1,60 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHandleParseError(t *testing.T) {
+	Convey("Handle a metric which cannot be processed", t, func() {
+		m := createMockMetric("nova-api.log", "invalid")
+		err := errors.New("No string match found")
+
+		Convey("In passthrough mode the metric is returned untouched", func() {
+			out := handleParseError(m, parseStageLogFormat, err, parseErrorPassthrough)
+			So(out, ShouldHaveLength, 1)
+			So(out[0], ShouldResemble, m)
+		})
+		Convey("In drop mode nothing is returned", func() {
+			out := handleParseError(m, parseStageLogFormat, err, parseErrorDrop)
+			So(out, ShouldBeEmpty)
+		})
+		Convey("In tag mode the metric is returned with error and stage tags", func() {
+			out := handleParseError(m, parseStageLogFormat, err, parseErrorTag)
+			So(out, ShouldHaveLength, 1)
+			So(out[0].Data, ShouldEqual, "invalid")
+			So(out[0].Tags, ShouldContainKey, "parse_error")
+			So(out[0].Tags["parse_error"], ShouldEqual, "No string match found")
+			So(out[0].Tags["parse_stage"], ShouldEqual, parseStageLogFormat)
+			So(m.Tags, ShouldNotContainKey, "parse_error")
+		})
+	})
+	Convey("Check mode of handling parse errors", t, func() {
+		for _, mode := range parseErrorModes {
+			So(checkParseErrorMode(mode), ShouldBeNil)
+		}
+		So(checkParseErrorMode("ignore"), ShouldNotBeNil)
+	})
+}
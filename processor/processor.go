@@ -22,7 +22,9 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/intelsdi-x/snap-plugin-lib-go/v1/plugin"
@@ -35,7 +37,7 @@ const (
 	//Version of the plugin
 	Version = 2
 
-	timeFormat = "2006-01-02 15:04:05 MST"
+	timeFormat = "2006-01-02 15:04:05"
 )
 
 const (
@@ -45,19 +47,39 @@ const (
 	//
 	// 	Example: 	2016-12-08 03:18:49.626 20 ERROR nova.api.openstack.extensions some_message
 	//
-	timestampRegexp = `(?P<timestamp>(\d{4})-(\d{2})-(\d{2})[( |T)](\d{2}):(\d{2}):(\d{2})[.]?(\d+)?)`
-	logRegexp       = timestampRegexp + `[ ](?P<pid>\d+)[ ](?P<severity_label>\S+)[ ](?P<python_module>\S+)[ ](?P<payload>(\n|.)*)`
+	// 	**Notice** that the timestamp might be in ISO 8601 form, i.e. with `T` separator and `Z` or an offset (e.g. `+02:00`)
+	//	which takes precedence over the configured timezone
+	timestampRegexp     = `(?P<timestamp>(\d{4})-(\d{2})-(\d{2})[ T](\d{2}):(\d{2}):(\d{2})([.,]\d+)?` + timestampZoneRegexp + `?)`
+	timestampZoneRegexp = `(Z|[+-]\d{2}(:?\d{2})?)`
+	logRegexp           = timestampRegexp + `[ ](?P<pid>\d+)[ ](?P<severity_label>\S+)[ ](?P<python_module>\S+)[ ](?P<payload>(\n|.)*)`
 
 	// ***	2) PATTERN FOR REQUEST CONTEXT   ***
 	// 	Openstack payload might include a request context which can tak multiple forms:
 	//	a) the case the capture produces nil: 					[-]
 	// 	b) the case the capture produces request_id: 				[req-b571ba10-0b4e-4411-a233-3df02488eae1 - - - - -]
 	// 	c) the case the capture produces request_id, user_id and tenant_id: 	[req-0c0b761c-47b0-4bf5-832c-89ef048fa56a fa2b2986c200431b8119035d4a47d420 b1ad1df9062a4fc682904c6c9b0f4e98 - - - ]
+	// 	d) the case the capture produces the full oslo.context field list, i.e. request_id, global_request_id, user_id, tenant_id,
+	// 	   domain, user_domain and project_domain:
+	// 	   [req-0c0b761c-47b0-4bf5-832c-89ef048fa56a req-b571ba10-0b4e-4411-a233-3df02488eae1 fa2b2986c200431b8119035d4a47d420 b1ad1df9062a4fc682904c6c9b0f4e98 default default default]
+	// 	e) the case the capture produces request_id, user_name and project_name, when user and project are identified by names
+	// 	   (i.a. `logging_user_identity_format = %(user_name)s %(project_name)s` or LDAP-backed keystone): [req-0c0b761c-47b0-4bf5-832c-89ef048fa56a admin demo]
 	//
 	// 	**Notice** that the `request_id` might be formatted as `req-xxx` or just `xxx` depending on the Openstack project
 	//	**Notice** that the uuid might be formatted as `b571ba10-0b4e-4411-a233-3df02488eae1` or `b571ba100b4e4411a2333df02488eae1`
+	//	**Notice** that fields which are not set are logged as `-` placeholders and they are not captured
+	//	**Notice** that `user_id` and `tenant_id` which are not uuid-shaped are stored as `user_name` and `project_name`
 	uuidRegexp           = `\S{8}[-]?\S{4}[-]?\S{4}[-]?\S{4}[-]?\S{12}`
-	requestContextRegexp = `\[(req-)?(?P<request_id>` + uuidRegexp + `)([ ](?P<user_id>` + uuidRegexp + `)[ ](?P<tenant_id>` + uuidRegexp + `))?.*\]`
+	contextFieldRegexp   = `[^\s\]]+`
+	requestContextRegexp = `\[(req-)?(?P<request_id>` + uuidRegexp + `)` +
+		`([ ]req-(?P<global_request_id>` + uuidRegexp + `))?` +
+		`([ ](?P<user_id>` + contextFieldRegexp + `)[ ](?P<tenant_id>` + contextFieldRegexp + `))?` +
+		`([ ](?P<domain>` + contextFieldRegexp + `)[ ](?P<user_domain>` + contextFieldRegexp + `)[ ](?P<project_domain>` + contextFieldRegexp + `))?` +
+		`.*\]`
+
+	// placeholder of request context field which is not set
+	contextPlaceholder = "-"
+	// strict form of uuid used to distinguish identifiers from names
+	uuidShapedRegexp = `^[[:xdigit:]]{8}[-]?[[:xdigit:]]{4}[-]?[[:xdigit:]]{4}[-]?[[:xdigit:]]{4}[-]?[[:xdigit:]]{12}$`
 
 	// ***	3) PATTERN FOR HTTP REQUEST CONTEXT   ***
 	// 	Openstack payload might include a HTTP request context which produces six values in this form:
@@ -69,37 +91,106 @@ const (
 	httpRequestContextRegexp = `"(?P<http_method>\w+)[ ](?P<http_url>.*)[ ]HTTP\/(?P<http_version>\d.\d)"[ ](status: )?(?P<http_status>\d+)[ ](len: )?(?P<http_response_size>\d+)[ ](time: )?(?P<http_response_time>\d+.\d+)`
 
 	// ***	4) PATTERN FOR HTTP REQUEST IP ADDRESSES   ***
-	// 	Capture for IP addresses <http_client_ip_addresses> and <http_server_ip_address>
+	// 	Capture for a chain of IP addresses <http_addresses> which immediately precedes HTTP request, the chain consists of
+	// 	X-Forwarded-For addresses (if any) followed by the address of the peer, i.a.:
 	//
-	// 	**Notice** that only `http_client_ip_addresses` is used and stored in metric' tags
-	ipAddressesRegexp          = `\d{1,3}[.]\d{1,3}[.]\d{1,3}[.]\d{1,3}`
-	httpRequestAddressesRegexp = `(?P<http_client_ip_address>` + ipAddressesRegexp + `)([,])?(?P<http_server_ip_address>` + ipAddressesRegexp + `)?`
+	//	Example:	10.91.126.38,10.0.0.1 "GET /v2.1/extensions HTTP/1.1" status: 200 len: 23011 time: 0.4711170
+	//	Example:	2001:db8::7, 10.1.1.1,[fe80::1%eth0]:8774 "GET /v2.1/extensions HTTP/1.1" status: 200 len: 23011 time: 0.4711170
+	//
+	// 	Each address of the chain is validated, the first address is stored as `http_client_ip_address`,
+	// 	the last one as `http_server_ip_address` and the full chain as `http_forwarded_chain` when it has more than two addresses
+	//
+	// 	**Notice** that IPv4, IPv6 (also bracketed, zone-suffixed and with a port) addresses are supported
+	ipAddressesRegexp          = `\[?[[:xdigit:]:.]*[[:xdigit:]](%[\w.-]+)?\]?(:\d+)?`
+	httpRequestAddressesRegexp = `(^|[\s(])(?P<http_addresses>` + ipAddressesRegexp + `(,[ ]?` + ipAddressesRegexp + `)*)([\s)]|$)`
 )
 
-// Plugin holds regular expressions and timezone needed to process openstack logs
+// Plugin holds the default parser and parsers configured per task which are needed to process openstack logs
 type Plugin struct {
+	// embedded default parser using built-in regular expressions and the host timezone
+	*parser
+
+	// parsers holds parsers created for distinct task configurations
+	parsers map[string]*parser
+	mutex   sync.Mutex
+
+	// tasks holds state of tasks kept between Process calls, see task.go
+	tasks map[string]*taskState
+	// correlations holds requests seen across Process calls when `correlation` is enabled
+	correlations *correlationIndex
+}
+
+// parser holds regular expressions and location of timestamps needed to process openstack logs
+type parser struct {
 	logRgx                  *regexp.Regexp
 	requestContextRgx       *regexp.Regexp
 	httpRequestContextRgx   *regexp.Regexp
 	httpRequestAddressesRgx *regexp.Regexp
-	timezone                string
+	location                *time.Location
+	// loggerElement, loggerRgx, loggerTemplate and logFileNaming define how a logger is retrieved, see logger.go
+	loggerElement  string
+	loggerRgx      *regexp.Regexp
+	loggerTemplate string
+	logFileNaming  string
+	// hostnameElement and hostname define how a hostname is retrieved, see logger.go
+	hostnameElement string
+	hostname        string
+	// redactionRgx is a user-defined regular expression of sensitive data or nil, see redaction.go
+	redactionRgx *regexp.Regexp
+	// formatRules select log formats for log files, see formats.go
+	formatRules []formatRule
+	// severities maps severity labels onto levels, see severity.go for handling of unknown labels
+	severities      map[string]int
+	unknownSeverity string
 }
 
+// timestampZoneRgx matches `Z` or an offset which ends a timestamp
+var timestampZoneRgx = regexp.MustCompile(timestampZoneRegexp + `$`)
+
+// uuidShapedRgx matches values of request context fields which are identifiers
+var uuidShapedRgx = regexp.MustCompile(uuidShapedRegexp)
+
+// contextNames maps request context fields onto fields used when their values are names instead of identifiers
+var contextNames = map[string]string{
+	"user_id":   "user_name",
+	"tenant_id": "project_name",
+}
+
+// severity maps severity labels onto syslog severity levels, it covers syslog names with their abbreviations
+// and labels used by python logging and oslo.log
 var severity = map[string]int{
 	"EMERGENCY": 0,
+	"EMERG":     0,
+	"PANIC":     0,
 	"ALERT":     1,
 	"CRITICAL":  2,
+	"CRIT":      2,
+	"FATAL":     2,
 	"ERROR":     3,
-	"WARNING":   4,
-	"NOTICE":    5,
-	"INFO":      6,
-	"DEBUG":     7,
+	"ERR":       3,
+	// older releases log lines of a traceback with TRACE label
+	"TRACE":   3,
+	"WARNING": 4,
+	"WARN":    4,
+	"NOTICE":  5,
+	// AUDIT is a level of oslo.log between INFO and WARNING
+	"AUDIT": 5,
+	// SYSTEM and NOTE are labels of MySQL and MariaDB
+	"SYSTEM": 5,
+	"INFO":   6,
+	"NOTE":   6,
+	"DEBUG":  7,
 }
 
 // New returns a new instance of the processor logs-openstack plugin with initialized regular expressions using
 // to parse log messages
 func New() *Plugin {
-	p := &Plugin{}
+	p := &Plugin{
+		parser:       &parser{},
+		parsers:      map[string]*parser{},
+		tasks:        map[string]*taskState{},
+		correlations: newCorrelationIndex(),
+	}
 
 	err := p.init()
 	if err != nil {
@@ -108,8 +199,8 @@ func New() *Plugin {
 	return p
 }
 
-// init compiles declared regular expressions to Regexp objects which are held in plugin structure
-// and sets timezone for timestamps
+// init compiles declared regular expressions to Regexp objects which are held in the default parser
+// and sets the host timezone for timestamps
 func (p *Plugin) init() error {
 	var err error
 	errors := []error{}
@@ -143,13 +234,25 @@ func (p *Plugin) init() error {
 		errors = append(errors, err)
 	}
 
-	p.timezone, _ = time.Now().Zone()
-	if p.timezone != "" {
+	if p.loggerRgx, err = regexp.Compile(loggerRegexp); err != nil {
 		log.WithFields(log.Fields{
-			"_block":    "init",
-			"_timezone": p.timezone,
-		}).Info("Set timezone for timestamps")
+			"_block": "init",
+			"_error": err,
+		}).Error("Cannot parse regular expression defined for logger")
+		errors = append(errors, err)
 	}
+	p.loggerElement = defaultLoggerElement
+	p.loggerTemplate = defaultLoggerTemplate
+	p.logFileNaming = logFileNamingDefault
+	p.hostnameElement = defaultHostnameElement
+
+	p.location = time.Local
+	p.severities = severity
+	p.unknownSeverity = unknownSeverityOmit
+	log.WithFields(log.Fields{
+		"_block":    "init",
+		"_timezone": p.location.String(),
+	}).Info("Set timezone for timestamps")
 
 	if len(errors) != 0 {
 		return fmt.Errorf("Cannot initialize processor plugin, invalid reqular expression(s), errors: %v", errors)
@@ -161,65 +264,191 @@ func (p *Plugin) init() error {
 // GetConfigPolicy returns the config policy
 func (p *Plugin) GetConfigPolicy() (plugin.ConfigPolicy, error) {
 	policy := plugin.NewConfigPolicy()
+
+	for _, name := range parserConfigs {
+		if err := policy.AddNewStringRule(configNamespace, name, false); err != nil {
+			return plugin.ConfigPolicy{}, err
+		}
+	}
+	for _, name := range optionConfigs {
+		if err := policy.AddNewBoolRule(configNamespace, name, false, plugin.SetDefaultBool(false)); err != nil {
+			return plugin.ConfigPolicy{}, err
+		}
+	}
+	if err := policy.AddNewIntRule(configNamespace, correlationTTLConfig, false,
+		plugin.SetDefaultInt(defaultCorrelationTTL), plugin.SetMinInt(1)); err != nil {
+		return plugin.ConfigPolicy{}, err
+	}
+	if err := policy.AddNewIntRule(configNamespace, correlationSizeConfig, false,
+		plugin.SetDefaultInt(defaultCorrelationSize), plugin.SetMinInt(1)); err != nil {
+		return plugin.ConfigPolicy{}, err
+	}
+	if err := policy.AddNewIntRule(configNamespace, suppressionWindowConfig, false,
+		plugin.SetDefaultInt(defaultSuppressionWindow), plugin.SetMinInt(1)); err != nil {
+		return plugin.ConfigPolicy{}, err
+	}
+	if err := policy.AddNewIntRule(configNamespace, suppressionLimitConfig, false,
+		plugin.SetDefaultInt(defaultSuppressionLimit), plugin.SetMinInt(1)); err != nil {
+		return plugin.ConfigPolicy{}, err
+	}
+	if err := policy.AddNewStringRule(configNamespace, onParseErrorConfig, false,
+		plugin.SetDefaultString(parseErrorPassthrough)); err != nil {
+		return plugin.ConfigPolicy{}, err
+	}
+	for _, name := range []string{minSeverityConfig, maxSeverityConfig} {
+		if err := policy.AddNewStringRule(configNamespace, name, false); err != nil {
+			return plugin.ConfigPolicy{}, err
+		}
+	}
+
 	return *policy, nil
 }
 
 // Process processes the data
-func (p *Plugin) Process(metrics []plugin.Metric, _ plugin.Config) ([]plugin.Metric, error) {
-	for i, m := range metrics {
+func (p *Plugin) Process(metrics []plugin.Metric, cfg plugin.Config) ([]plugin.Metric, error) {
+	prs, err := p.getParser(cfg)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"_block": "Process",
+			"_error": err,
+		}).Error("Invalid configuration of processor")
+		return nil, err
+	}
+
+	opts, err := getOptions(cfg, prs)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"_block": "Process",
+			"_error": err,
+		}).Error("Invalid configuration of processor")
+		return nil, err
+	}
 
-		logger, err := getLoggerInfo(m.Namespace)
+	task := p.getTaskState(cfg)
+	stats := &parseStats{received: int64(len(metrics))}
+	processed := make([]plugin.Metric, 0, len(metrics))
+	for _, m := range metrics {
+
+		loggerTags, logFile, err := prs.getLoggerInfo(m.Namespace)
 		if err != nil {
+			stats.missingLogFile++
 			log.WithFields(log.Fields{
 				"_block":  "Process",
 				"_metric": m.Namespace.Strings(),
 				"_data":   m.Data,
 				"_error":  err,
 			}).Warning("Cannot retrieve logger info")
+			processed = append(processed, handleParseError(m, parseStageLoggerInfo, err, opts.onParseError)...)
 			continue
 		}
 
-		data, ok := m.Data.(string)
-		if !ok {
+		// a metric might carry a batch of log records, each of them is processed as a separate metric
+		records, err := prs.getRecords(logFile, m.Data)
+		if err != nil {
+			stats.nonStringData++
 			log.WithFields(log.Fields{
 				"_block":  "Process",
 				"_metric": m.Namespace.Strings(),
 				"_data":   m.Data,
 				"_error":  "unexpected data type",
 			}).Warning("Plugin processes only string logs")
+			processed = append(processed, handleParseError(m, parseStageDataType, err, opts.onParseError)...)
 			continue
 		}
+		stats.records += int64(len(records))
+
+		for _, data := range records {
+			// a metric of the record keeps the namespace and tags of the incoming metric, its data is the record
+			// as a string, so it is handled in the same way whatever the data type of the incoming metric is
+			rm := m
+			rm.Data = data
+
+			// strip a wrapper of container runtime, so the wrapped log is parsed
+			unwrapped, containerTags := unwrapContainerLog(data)
+
+			timestamp, msg, fields, err := prs.processLog(logFile, unwrapped)
+			if err != nil {
+				stats.unparsable++
+				log.WithFields(log.Fields{
+					"_block":  "Process",
+					"_metric": rm.Namespace.Strings(),
+					"_data":   rm.Data,
+					"_error":  err,
+				}).Warning("Invalid format of log block")
+				processed = append(processed, handleParseError(rm, parseStageLogFormat, err, opts.onParseError)...)
+				continue
+			}
 
-		timestamp, msg, fields, err := p.processOpenstackLog(data)
-		if err != nil {
-			log.WithFields(log.Fields{
-				"_block":  "Process",
-				"_metric": m.Namespace.Strings(),
-				"_data":   m.Data,
-				"_error":  err,
-			}).Warning("Invalid format of log block")
-			continue
-		}
+			stats.parsed++
+			if _, exist := fields["request_id"]; exist {
+				stats.withRequestContext++
+			} else {
+				stats.withoutRequestContext++
+			}
 
-		if msg != "" {
-			// for not empty msg, do retrieving a request context
-			mergeMaps(fields, p.getRequestContext(msg))
-			mergeMaps(fields, p.getHTTPRequestContext(msg))
+			// drop logs which are less or more severe than configured for the logger
+			if !opts.severityInRange(loggerTags["logger"], fields) {
+				stats.filtered++
+				continue
+			}
+
+			// overwrite metric's timestamp and data with values retrieved from log
+			rm.Timestamp = timestamp
+			rm.Data = msg
+
+			// add other info retrieved from log as metric's tag, tags are copied as they are shared by records
+			rm.Tags = make(map[string]string, len(m.Tags)+len(loggerTags)+len(fields)+len(containerTags))
+			mergeMaps(rm.Tags, m.Tags)
+			mergeMaps(rm.Tags, loggerTags)
+			mergeMaps(rm.Tags, fields)
+			mergeMaps(rm.Tags, containerTags)
+
+			if opts.fingerprint {
+				mergeMaps(rm.Tags, getFingerprint(msg, rm.Tags))
+			}
+
+			if opts.correlation {
+				mergeMaps(rm.Tags, p.correlations.correlate(rm, opts.correlationTTL, opts.correlationSize))
+			}
+
+			// numeric metrics are derived before the metric might be held as a part of traceback
+			var derived []plugin.Metric
+			if opts.httpMetrics {
+				derived = getHTTPMetrics(rm)
+			}
+
+			ready := []plugin.Metric{rm}
+			if opts.multilineTraceback {
+				// consecutive lines of a traceback are emitted as one metric
+				ready = task.tracebacks.join(rm)
+			}
+			processed = append(processed, task.suppress(ready, opts, stats)...)
+			processed = append(processed, derived...)
 		}
+	}
 
-		// overwrite metric's timestamp and data with values retrieved from log
-		metrics[i].Timestamp = timestamp
-		metrics[i].Data = msg
+	if opts.multilineTraceback {
+		processed = append(processed, task.suppress(task.tracebacks.flush(), opts, stats)...)
+	}
 
-		// add other info retrieved from log as metric's tag
-		metrics[i].Tags["logger"] = logger
+	if opts.suppression {
+		// summaries of suppressed logs are emitted at the latest at the end of the next Process call
+		processed = append(processed, task.suppressions.flush()...)
+	}
 
-		for k, v := range fields {
-			metrics[i].Tags[k] = v
+	if opts.redaction {
+		// sensitive data are redacted in all metrics, also in the ones which were not parsed
+		for i := range processed {
+			processed[i] = redact(processed[i], prs.redactionRgx)
 		}
 	}
 
-	return metrics, nil
+	if opts.statsMetrics {
+		// running totals of the task are emitted, so they might be alerted on with a rate
+		processed = append(processed, task.addStats(stats).metrics(time.Now())...)
+	}
+
+	return processed, nil
 }
 
 // parse returns regular expression matches found in incoming data
@@ -248,8 +477,14 @@ func parse(data string, rexp *regexp.Regexp) (map[string]string, error) {
 
 // processOpenstackLog processes incoming openstack log and retrieves based on regular expression `logRgx` such info like
 // log's timestamp, message and others fields (i.a. `pid`, `severity_label`, `severity`, `python_module`)
+// The timestamp is interpreted in the parser's location, so daylight saving time is taken into account
 // An error is returned if incoming data does not fit for openstack-log pattern
-func (p *Plugin) processOpenstackLog(data string) (timestamp time.Time, msg string, fields map[string]string, err error) {
+func (p *parser) processOpenstackLog(data string) (timestamp time.Time, msg string, fields map[string]string, err error) {
+	if isJSONLog(data) {
+		// log formatted by oslo_log.formatters.JSONFormatter
+		return p.processJSONLog(data)
+	}
+
 	fields, err = parse(data, p.logRgx)
 	if err != nil {
 		return
@@ -264,7 +499,7 @@ func (p *Plugin) processOpenstackLog(data string) (timestamp time.Time, msg stri
 	delete(fields, "timestamp")
 
 	// parse timestamp to time.Time type
-	timestamp, err = time.Parse(timeFormat, fmt.Sprintf("%s %s", timestampStr, p.timezone))
+	timestamp, err = parseTimestamp(timestampStr, p.location)
 	if err != nil {
 		return
 	}
@@ -277,19 +512,61 @@ func (p *Plugin) processOpenstackLog(data string) (timestamp time.Time, msg stri
 	}
 	delete(fields, "payload")
 
-	// set an appropriate `severity` into fields map based on label from `severity_label`
-	// for example, for `severity_label` = "INFO", set `severity` = "6"
-	// both `severity_label` and `severity` should be kept in fields map
-	if label, ok := fields["severity_label"]; ok {
-		fields["severity"] = fmt.Sprintf("%d", severity[label])
-	}
+	err = p.setSeverity(fields)
 
 	return timestamp, msg, fields, err
 }
 
+// parseTimestamp parses a timestamp in form "2006-01-02 15:04:05" with optional fraction of second (up to nanoseconds),
+// `T` might be used as a separator and a comma as a decimal mark; a timestamp without `Z` or an offset is interpreted
+// in the location
+func parseTimestamp(ts string, location *time.Location) (time.Time, error) {
+	ts = strings.Replace(strings.Replace(ts, "T", " ", 1), ",", ".", 1)
+
+	if i := timestampZoneRgx.FindStringIndex(ts); i != nil {
+		zone := ts[i[0]:]
+		ts = ts[:i[0]]
+		if zone == "Z" {
+			location = time.UTC
+		} else {
+			var err error
+			if location, err = loadLocation(zone); err != nil {
+				return time.Time{}, fmt.Errorf("Invalid offset `%s` of timestamp: %v", zone, err)
+			}
+		}
+	}
+	return time.ParseInLocation(timeFormat, ts, location)
+}
+
+// setSeverity sets an appropriate `severity` into fields map based on label from `severity_label`
+// for example, for `severity_label` = "INFO", set `severity` = "6"
+// both `severity_label` and `severity` should be kept in fields map
+// An unknown label is handled according to the parser's `unknownSeverity` mode
+func (p *parser) setSeverity(fields map[string]string) error {
+	label, ok := fields["severity_label"]
+	if !ok {
+		return nil
+	}
+	if level, exist := p.severities[strings.ToUpper(label)]; exist {
+		fields["severity"] = strconv.Itoa(level)
+		return nil
+	}
+
+	switch p.unknownSeverity {
+	case unknownSeverityOmit:
+		return nil
+	case unknownSeverityFail:
+		return fmt.Errorf("Unknown severity label `%s`", label)
+	}
+	// otherwise the mode is a level assigned to unknown labels
+	level, _ := getSeverityLevel(p.unknownSeverity, p.severities)
+	fields["severity"] = strconv.Itoa(level)
+	return nil
+}
+
 // getRequestContext parses incoming msg to return all found matches of regular expression `requestContextRgx`
 // or nil when there is no request context in message
-func (p *Plugin) getRequestContext(msg string) map[string]string {
+func (p *parser) getRequestContext(msg string) map[string]string {
 	requestContext, err := parse(msg, p.requestContextRgx)
 	if err != nil {
 		log.WithFields(log.Fields{
@@ -300,12 +577,30 @@ func (p *Plugin) getRequestContext(msg string) map[string]string {
 		return nil
 	}
 
+	// skip fields which are not set
+	for key, val := range requestContext {
+		if val == contextPlaceholder {
+			delete(requestContext, key)
+		}
+	}
+
+	setContextNames(requestContext)
 	return requestContext
 }
 
+// setContextNames moves user and project which are identified by names instead of ids onto fields of `contextNames`
+func setContextNames(fields map[string]string) {
+	for idKey, nameKey := range contextNames {
+		if val, ok := fields[idKey]; ok && !uuidShapedRgx.MatchString(val) {
+			delete(fields, idKey)
+			fields[nameKey] = val
+		}
+	}
+}
+
 // getHTTPRequestContext parses msg to return all matches of regular expressions `httpRequestContextRgx` and
 // `httpRequestAddressesRgx` (optional) or nil when there is no request HTTP context in message
-func (p *Plugin) getHTTPRequestContext(msg string) map[string]string {
+func (p *parser) getHTTPRequestContext(msg string) map[string]string {
 	httpRequestContext, err := parse(msg, p.httpRequestContextRgx)
 	if err != nil {
 		log.WithFields(log.Fields{
@@ -316,7 +611,7 @@ func (p *Plugin) getHTTPRequestContext(msg string) map[string]string {
 		return nil
 	}
 
-	if httpRequestAddresses, err := parse(msg, p.httpRequestAddressesRgx); err == nil {
+	if httpRequestAddresses, err := p.getHTTPRequestAddresses(msg); err == nil {
 		mergeMaps(httpRequestContext, httpRequestAddresses)
 	} else {
 		log.WithFields(log.Fields{
@@ -329,25 +624,6 @@ func (p *Plugin) getHTTPRequestContext(msg string) map[string]string {
 	return httpRequestContext
 }
 
-// getLoggerInfo returns logger in form "openstack.<service_name>", where `service_name` is retrieved from metric's namespace
-func getLoggerInfo(ns plugin.Namespace) (string, error) {
-	isDynamic, indexes := ns.IsDynamic()
-	if !isDynamic {
-		return "", fmt.Errorf("Metric `%v` is expected to contain a dynamic element, but it doesn't", ns.Strings())
-	}
-	// take the last dynamic element which is expected to be named `log_file`
-	lde := ns.Element(indexes[len(indexes)-1])
-	if lde.Name != "log_file" {
-		return "", fmt.Errorf("Metric `%v` is expected to contain a dynamic element `log_file`, but it doesn't", ns.Strings())
-	}
-	logFileName := strings.TrimSuffix(lde.Value, ".log")
-
-	// serviceName equals the first part of logFileName splitted by the '-' separator
-	serviceName := strings.Split(logFileName, "-")[0]
-
-	return fmt.Sprintf("openstack.%s", serviceName), nil
-}
-
 // mergeMaps merges `src` map into `dst`, in case they have the same key, dst attributes will be overwritten
 // by src attribute values
 func mergeMaps(dst map[string]string, src map[string]string) {
@@ -22,6 +22,7 @@ package processor
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -168,6 +169,56 @@ func TestProcessOpenstackLog(t *testing.T) {
 					"\"GET /v2.1/b1ad1df9062a4fc682904c6c9b0f4e98/extensions HTTP/1.1\" status: 200 len: 23011 time: 0.4711170")
 				So(err, ShouldBeNil)
 			})
+			Convey("for log with an ISO 8601 timestamp", func() {
+				timestamp, _, _, err := processor.processOpenstackLog("2016-12-08T03:18:49.626Z 20 ERROR nova.api.openstack.extensions [-] Unexpected exception.")
+				So(err, ShouldBeNil)
+				So(timestamp.Equal(time.Date(2016, 12, 8, 3, 18, 49, 626000000, time.UTC)), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestParseTimestamp(t *testing.T) {
+	Convey("Parse timestamps of logs", t, func() {
+		location := time.FixedZone("UTC+01:00", 3600)
+
+		Convey("should return an error for invalid timestamp", func() {
+			_, err := parseTimestamp("2016-12-08 03:18", location)
+			So(err, ShouldNotBeNil)
+		})
+		Convey("should interpret timestamp without a zone in the location", func() {
+			timestamp, err := parseTimestamp("2016-12-08 03:18:49.626", location)
+			So(err, ShouldBeNil)
+			So(timestamp.Equal(time.Date(2016, 12, 8, 2, 18, 49, 626000000, time.UTC)), ShouldBeTrue)
+		})
+		Convey("should accept `T` separator and comma as a decimal mark", func() {
+			timestamp, err := parseTimestamp("2016-12-08T03:18:49,626", location)
+			So(err, ShouldBeNil)
+			So(timestamp.Equal(time.Date(2016, 12, 8, 2, 18, 49, 626000000, time.UTC)), ShouldBeTrue)
+		})
+		Convey("should keep nanoseconds", func() {
+			timestamp, err := parseTimestamp("2016-12-08T03:18:49.123456789", location)
+			So(err, ShouldBeNil)
+			So(timestamp.Nanosecond(), ShouldEqual, 123456789)
+		})
+		Convey("should accept timestamp without a fraction of second", func() {
+			timestamp, err := parseTimestamp("2016-12-08T03:18:49", location)
+			So(err, ShouldBeNil)
+			So(timestamp.Equal(time.Date(2016, 12, 8, 2, 18, 49, 0, time.UTC)), ShouldBeTrue)
+		})
+		Convey("should interpret `Z` as UTC", func() {
+			timestamp, err := parseTimestamp("2016-12-08T03:18:49.626Z", location)
+			So(err, ShouldBeNil)
+			So(timestamp.Equal(time.Date(2016, 12, 8, 3, 18, 49, 626000000, time.UTC)), ShouldBeTrue)
+		})
+		Convey("should prefer an offset over the location", func() {
+			timestamp, err := parseTimestamp("2016-12-08T03:18:49.626+02:00", location)
+			So(err, ShouldBeNil)
+			So(timestamp.Equal(time.Date(2016, 12, 8, 1, 18, 49, 626000000, time.UTC)), ShouldBeTrue)
+
+			timestamp, err = parseTimestamp("2016-12-08 03:18:49-0500", location)
+			So(err, ShouldBeNil)
+			So(timestamp.Equal(time.Date(2016, 12, 8, 8, 18, 49, 0, time.UTC)), ShouldBeTrue)
 		})
 	})
 }
@@ -221,6 +272,62 @@ func TestGetRequestContext(t *testing.T) {
 						So(output["tenant_id"], ShouldEqual, "b1ad1df9062a4fc682904c6c9b0f4e98")
 					})
 				})
+				Convey("so output should contain domains which are set", func() {
+					So(output, ShouldNotContainKey, "domain")
+					So(output["user_domain"], ShouldEqual, "default")
+					So(output["project_domain"], ShouldEqual, "default")
+				})
+			})
+			Convey("when message contains only request_id, user_id and tenant_id", func() {
+				msg := "[req-0c0b761c-47b0-4bf5-832c-89ef048fa56a fa2b2986c200431b8119035d4a47d420 b1ad1df9062a4fc682904c6c9b0f4e98] Creating server"
+				output := processor.getRequestContext(msg)
+				So(output, ShouldResemble, map[string]string{
+					"request_id": "0c0b761c-47b0-4bf5-832c-89ef048fa56a",
+					"user_id":    "fa2b2986c200431b8119035d4a47d420",
+					"tenant_id":  "b1ad1df9062a4fc682904c6c9b0f4e98",
+				})
+			})
+			Convey("when message contains request context with global request id", func() {
+				msg := "[req-0c0b761c-47b0-4bf5-832c-89ef048fa56a req-b571ba10-0b4e-4411-a233-3df02488eae1 fa2b2986c200431b8119035d4a47d420 " +
+					"b1ad1df9062a4fc682904c6c9b0f4e98 Default users_domain projects_domain] Creating server"
+				output := processor.getRequestContext(msg)
+				So(output, ShouldResemble, map[string]string{
+					"request_id":        "0c0b761c-47b0-4bf5-832c-89ef048fa56a",
+					"global_request_id": "b571ba10-0b4e-4411-a233-3df02488eae1",
+					"user_id":           "fa2b2986c200431b8119035d4a47d420",
+					"tenant_id":         "b1ad1df9062a4fc682904c6c9b0f4e98",
+					"domain":            "Default",
+					"user_domain":       "users_domain",
+					"project_domain":    "projects_domain",
+				})
+			})
+			Convey("when message contains request context with user and project names", func() {
+				msg := "[req-0c0b761c-47b0-4bf5-832c-89ef048fa56a admin demo - default default] Creating server"
+				output := processor.getRequestContext(msg)
+				So(output, ShouldResemble, map[string]string{
+					"request_id":     "0c0b761c-47b0-4bf5-832c-89ef048fa56a",
+					"user_name":      "admin",
+					"project_name":   "demo",
+					"user_domain":    "default",
+					"project_domain": "default",
+				})
+			})
+			Convey("when message contains request context with user id and project name", func() {
+				msg := "[req-0c0b761c-47b0-4bf5-832c-89ef048fa56a fa2b2986-c200-431b-8119-035d4a47d420 demo] Creating server"
+				output := processor.getRequestContext(msg)
+				So(output, ShouldResemble, map[string]string{
+					"request_id":   "0c0b761c-47b0-4bf5-832c-89ef048fa56a",
+					"user_id":      "fa2b2986-c200-431b-8119-035d4a47d420",
+					"project_name": "demo",
+				})
+			})
+			Convey("when message contains request context with global request id and placeholders", func() {
+				msg := "[req-0c0b761c-47b0-4bf5-832c-89ef048fa56a req-b571ba10-0b4e-4411-a233-3df02488eae1 - - - - -] Periodic task"
+				output := processor.getRequestContext(msg)
+				So(output, ShouldResemble, map[string]string{
+					"request_id":        "0c0b761c-47b0-4bf5-832c-89ef048fa56a",
+					"global_request_id": "b571ba10-0b4e-4411-a233-3df02488eae1",
+				})
 			})
 		})
 	})
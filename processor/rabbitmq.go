@@ -0,0 +1,95 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// ***	PATTERN FOR RABBITMQ LOG   ***
+	// 	RabbitMQ 3.7 and newer logs messages in the following form, newer releases add an offset to the timestamp:
+	// 	<timestamp> [<severity_label>] <<erlang_pid>> <payload>
+	//
+	// 	Example:	2016-12-07 03:26:24.254 [info] <0.197.0> Starting RabbitMQ 3.7.4 on Erlang 20.2
+	//
+	rabbitmqLogRegexp = `^(?P<timestamp>\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}(\.\d+)?)(?P<offset>[+-]\d{2}:\d{2})? ` +
+		`\[(?P<severity_label>[a-z]+)\] <(?P<erlang_pid>[\d.]+)> (?P<payload>(\n|.)*)`
+
+	// 	Older releases log messages as reports, where the payload starts in the next line:
+	// 	=<severity_label> REPORT==== <timestamp> ===
+	//
+	// 	Example:	=INFO REPORT==== 7-Dec-2016::03:26:24 ===
+	// 			accepting AMQP connection <0.1330.0> (10.0.0.1:49738 -> 10.0.0.1:5672)
+	//
+	rabbitmqReportRegexp = `^=(?P<severity_label>[A-Z]+) REPORT==== (?P<timestamp>\d{1,2}-[A-Za-z]{3}-\d{4}::\d{2}:\d{2}:\d{2}) ===\n` +
+		`(?P<payload>(\n|.)*)`
+
+	// rabbitmqRecordStartRegexp matches a beginning of log in any of the above forms
+	rabbitmqRecordStartRegexp = `^(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}(\.\d+)?([+-]\d{2}:\d{2})? \[[a-z]+\] <|=[A-Z]+ REPORT==== )`
+
+	rabbitmqReportTimeFormat = "2-Jan-2006::15:04:05"
+	rabbitmqOffsetTimeFormat = timeFormat + "-07:00"
+)
+
+var (
+	rabbitmqLogRgx    = regexp.MustCompile(rabbitmqLogRegexp)
+	rabbitmqReportRgx = regexp.MustCompile(rabbitmqReportRegexp)
+
+	rabbitmqRecordStartRgx = regexp.MustCompile(rabbitmqRecordStartRegexp)
+)
+
+// rabbitmqFormat is a format of RabbitMQ logs
+type rabbitmqFormat struct{}
+
+func (rabbitmqFormat) parse(p *parser, data string) (timestamp time.Time, msg string, fields map[string]string, err error) {
+	layout := timeFormat
+	if fields, err = parse(data, rabbitmqLogRgx); err != nil {
+		if fields, err = parse(data, rabbitmqReportRgx); err != nil {
+			return
+		}
+		layout = rabbitmqReportTimeFormat
+	}
+
+	if offset, ok := fields["offset"]; ok {
+		// a timestamp with offset does not depend on the parser's location
+		timestamp, err = time.Parse(rabbitmqOffsetTimeFormat, fields["timestamp"]+offset)
+		delete(fields, "offset")
+	} else {
+		timestamp, err = time.ParseInLocation(layout, fields["timestamp"], p.location)
+	}
+	if err != nil {
+		return
+	}
+	delete(fields, "timestamp")
+
+	msg = strings.TrimSpace(fields["payload"])
+	delete(fields, "payload")
+
+	if err = p.setSeverity(fields); err != nil {
+		return
+	}
+	return timestamp, msg, fields, nil
+}
+
+func (rabbitmqFormat) startsRecord(line string) bool {
+	return rabbitmqRecordStartRgx.MatchString(line)
+}
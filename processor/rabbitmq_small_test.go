@@ -0,0 +1,68 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRabbitmqFormat(t *testing.T) {
+	Convey("Create logs-openstack processor", t, func() {
+		processor := New()
+		So(processor, ShouldNotBeNil)
+		format := rabbitmqFormat{}
+
+		Convey("log of RabbitMQ 3.7 should be parsed", func() {
+			timestamp, msg, fields, err := format.parse(processor.parser, "2016-12-07 03:26:24.254 [warning] <0.1330.0> closing AMQP connection")
+			So(err, ShouldBeNil)
+			So(timestamp, ShouldResemble, time.Date(2016, 12, 7, 3, 26, 24, 254000000, time.Local))
+			So(msg, ShouldEqual, "closing AMQP connection")
+			So(fields, ShouldResemble, map[string]string{
+				"severity_label": "warning",
+				"severity":       "4",
+				"erlang_pid":     "0.1330.0",
+			})
+		})
+		Convey("timestamp with offset should not depend on timezone", func() {
+			timestamp, _, _, err := format.parse(processor.parser, "2016-12-07 03:26:24.254123+02:00 [info] <0.197.0> Starting RabbitMQ")
+			So(err, ShouldBeNil)
+			So(timestamp.UTC(), ShouldResemble, time.Date(2016, 12, 7, 1, 26, 24, 254123000, time.UTC))
+		})
+		Convey("report of older releases should be parsed", func() {
+			timestamp, msg, fields, err := format.parse(processor.parser, "=INFO REPORT==== 7-Dec-2016::03:26:24 ===\n"+
+				"accepting AMQP connection <0.1330.0> (10.0.0.1:49738 -> 10.0.0.1:5672)\n")
+			So(err, ShouldBeNil)
+			So(timestamp, ShouldResemble, time.Date(2016, 12, 7, 3, 26, 24, 0, time.Local))
+			So(msg, ShouldEqual, "accepting AMQP connection <0.1330.0> (10.0.0.1:49738 -> 10.0.0.1:5672)")
+			So(fields, ShouldResemble, map[string]string{
+				"severity_label": "INFO",
+				"severity":       "6",
+			})
+		})
+		Convey("log in other format should give an error", func() {
+			_, _, _, err := format.parse(processor.parser, "2016-12-07 03:26:24.254 7 INFO nova.console.websocketproxy [-] handler exception")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,162 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// modes of handling severity labels missing in the mapping, set by config item `unknown_severity`;
+	// besides them the mode might be a severity label or a level (0-7) which is assigned to unknown labels
+	unknownSeverityOmit = "omit"
+	unknownSeverityFail = "fail"
+)
+
+// parseSeverityMapping returns the built-in mapping of severity labels extended with items of config value,
+// which has a form of comma separated list of `<label>=<level>`, where level is a number (0-7) or a label known
+// in the built-in mapping or added by a preceding item
+func parseSeverityMapping(val string) (map[string]int, error) {
+	mapping := make(map[string]int, len(severity))
+	for label, level := range severity {
+		mapping[label] = level
+	}
+
+	for _, item := range strings.Split(val, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		i := strings.Index(item, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("missing level of severity label in `%s`", item)
+		}
+		label := strings.ToUpper(strings.TrimSpace(item[:i]))
+		if label == "" {
+			return nil, fmt.Errorf("missing severity label in `%s`", item)
+		}
+		level, err := getSeverityLevel(strings.TrimSpace(item[i+1:]), mapping)
+		if err != nil {
+			return nil, err
+		}
+		mapping[label] = level
+	}
+	return mapping, nil
+}
+
+// checkUnknownSeverity returns the mode of handling unknown severity labels or an error if it is invalid,
+// a label assigned to unknown labels is looked up in the mapping
+func checkUnknownSeverity(mode string, mapping map[string]int) (string, error) {
+	mode = strings.TrimSpace(mode)
+	switch strings.ToLower(mode) {
+	case unknownSeverityOmit, unknownSeverityFail:
+		return strings.ToLower(mode), nil
+	}
+	if _, err := getSeverityLevel(mode, mapping); err != nil {
+		return "", fmt.Errorf("`%s` is neither one of %v nor a known severity", mode,
+			[]string{unknownSeverityOmit, unknownSeverityFail})
+	}
+	return mode, nil
+}
+
+// severityBound holds a bound of severity set by config item `min_severity` or `max_severity`, which has a form
+// of comma separated list of a default bound and bounds overridden for loggers, e.g. "INFO,openstack.neutron=WARNING"
+type severityBound struct {
+	// def is a bound applied to loggers without an override or -1 if there is none
+	def     int
+	loggers map[string]int
+}
+
+// parseSeverityBound returns a bound parsed from value of config item or nil if the value is empty,
+// labels are looked up in the mapping
+func parseSeverityBound(val string, mapping map[string]int) (*severityBound, error) {
+	if strings.TrimSpace(val) == "" {
+		return nil, nil
+	}
+
+	bound := &severityBound{def: -1, loggers: map[string]int{}}
+	for _, item := range strings.Split(val, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		logger := ""
+		label := item
+		if i := strings.Index(item, "="); i >= 0 {
+			logger = strings.TrimSpace(item[:i])
+			label = strings.TrimSpace(item[i+1:])
+			if logger == "" {
+				return nil, fmt.Errorf("missing logger name in `%s`", item)
+			}
+		}
+
+		level, err := getSeverityLevel(label, mapping)
+		if err != nil {
+			return nil, err
+		}
+		if logger == "" {
+			bound.def = level
+		} else {
+			bound.loggers[logger] = level
+		}
+	}
+	return bound, nil
+}
+
+// get returns a bound of severity for the logger and false if the logger is not bounded
+func (b *severityBound) get(logger string) (int, bool) {
+	if b == nil {
+		return 0, false
+	}
+	if level, exist := b.loggers[logger]; exist {
+		return level, true
+	}
+	return b.def, b.def >= 0
+}
+
+// getSeverityLevel returns a severity level for a label known in the mapping or a numeric level (0-7)
+func getSeverityLevel(label string, mapping map[string]int) (int, error) {
+	if level, exist := mapping[strings.ToUpper(label)]; exist {
+		return level, nil
+	}
+	if level, err := strconv.Atoi(label); err == nil && level >= 0 && level <= 7 {
+		return level, nil
+	}
+	return 0, fmt.Errorf("unknown severity `%s`", label)
+}
+
+// severityInRange returns true if severity of a log is within bounds set for its logger; the lower level is
+// the more severe log is, so `min_severity` bounds level from above and `max_severity` from below;
+// logs without severity are always in range
+func (o *options) severityInRange(logger string, fields map[string]string) bool {
+	level, err := strconv.Atoi(fields["severity"])
+	if err != nil {
+		return true
+	}
+	if min, ok := o.minSeverity.get(logger); ok && level > min {
+		return false
+	}
+	if max, ok := o.maxSeverity.get(logger); ok && level < max {
+		return false
+	}
+	return true
+}
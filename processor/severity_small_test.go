@@ -0,0 +1,164 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseSeverityBound(t *testing.T) {
+	Convey("Parse bound of severity", t, func() {
+		Convey("Empty value gives no bound", func() {
+			bound, err := parseSeverityBound("", severity)
+			So(err, ShouldBeNil)
+			So(bound, ShouldBeNil)
+			_, ok := bound.get("openstack.nova")
+			So(ok, ShouldBeFalse)
+		})
+		Convey("Default bound and overrides for loggers", func() {
+			bound, err := parseSeverityBound("info, openstack.neutron=WARNING,openstack.glance=3", severity)
+			So(err, ShouldBeNil)
+
+			level, ok := bound.get("openstack.nova")
+			So(ok, ShouldBeTrue)
+			So(level, ShouldEqual, 6)
+			level, ok = bound.get("openstack.neutron")
+			So(ok, ShouldBeTrue)
+			So(level, ShouldEqual, 4)
+			level, ok = bound.get("openstack.glance")
+			So(ok, ShouldBeTrue)
+			So(level, ShouldEqual, 3)
+		})
+		Convey("Overrides only bound the given loggers", func() {
+			bound, err := parseSeverityBound("openstack.neutron=WARNING", severity)
+			So(err, ShouldBeNil)
+			_, ok := bound.get("openstack.nova")
+			So(ok, ShouldBeFalse)
+		})
+		Convey("Invalid values give an error", func() {
+			for _, val := range []string{"VERBOSE", "8", "=INFO", "openstack.nova=-1"} {
+				_, err := parseSeverityBound(val, severity)
+				So(err, ShouldNotBeNil)
+			}
+		})
+		Convey("Labels of configured mapping are known", func() {
+			mapping, err := parseSeverityMapping("VERBOSE=7")
+			So(err, ShouldBeNil)
+			bound, err := parseSeverityBound("VERBOSE", mapping)
+			So(err, ShouldBeNil)
+			level, ok := bound.get("openstack.nova")
+			So(ok, ShouldBeTrue)
+			So(level, ShouldEqual, 7)
+		})
+	})
+}
+
+func TestSeverityInRange(t *testing.T) {
+	Convey("Check if severity is within configured range", t, func() {
+		minSeverity, _ := parseSeverityBound("INFO,openstack.neutron=WARNING", severity)
+		maxSeverity, _ := parseSeverityBound("openstack.nova=ERROR", severity)
+		opts := &options{minSeverity: minSeverity, maxSeverity: maxSeverity}
+
+		So(opts.severityInRange("openstack.nova", map[string]string{"severity": "6"}), ShouldBeTrue)
+		So(opts.severityInRange("openstack.nova", map[string]string{"severity": "7"}), ShouldBeFalse)
+		So(opts.severityInRange("openstack.nova", map[string]string{"severity": "2"}), ShouldBeFalse)
+		So(opts.severityInRange("openstack.neutron", map[string]string{"severity": "6"}), ShouldBeFalse)
+		So(opts.severityInRange("openstack.neutron", map[string]string{"severity": "2"}), ShouldBeTrue)
+		So(opts.severityInRange("openstack.neutron", map[string]string{}), ShouldBeTrue)
+	})
+}
+
+func TestParseSeverityMapping(t *testing.T) {
+	Convey("Parse mapping of severity labels", t, func() {
+		Convey("Items extend and override the built-in mapping", func() {
+			mapping, err := parseSeverityMapping("verbose=7, AUDIT=INFO")
+			So(err, ShouldBeNil)
+			So(mapping["VERBOSE"], ShouldEqual, 7)
+			So(mapping["AUDIT"], ShouldEqual, 6)
+			So(mapping["WARN"], ShouldEqual, 4)
+			So(severity["AUDIT"], ShouldEqual, 5)
+		})
+		Convey("Invalid items give an error", func() {
+			for _, val := range []string{"VERBOSE", "=7", "VERBOSE=8", "VERBOSE=LOUD"} {
+				_, err := parseSeverityMapping(val)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+}
+
+func TestCheckUnknownSeverity(t *testing.T) {
+	Convey("Check mode of handling unknown severity labels", t, func() {
+		for _, mode := range []string{"omit", "fail", "Fail", "DEBUG", "7"} {
+			_, err := checkUnknownSeverity(mode, severity)
+			So(err, ShouldBeNil)
+		}
+		for _, mode := range []string{"ignore", "8", ""} {
+			_, err := checkUnknownSeverity(mode, severity)
+			So(err, ShouldNotBeNil)
+		}
+		mapping, err := parseSeverityMapping("VERBOSE=7")
+		So(err, ShouldBeNil)
+		_, err = checkUnknownSeverity("VERBOSE", mapping)
+		So(err, ShouldBeNil)
+	})
+}
+
+func TestSetSeverity(t *testing.T) {
+	Convey("Create logs-openstack processor", t, func() {
+		processor := New()
+		So(processor, ShouldNotBeNil)
+
+		Convey("Labels of built-in mapping are known", func() {
+			for label, level := range map[string]string{"TRACE": "3", "AUDIT": "5", "WARN": "4", "FATAL": "2", "CRIT": "2", "info": "6"} {
+				fields := map[string]string{"severity_label": label}
+				So(processor.setSeverity(fields), ShouldBeNil)
+				So(fields["severity"], ShouldEqual, level)
+			}
+		})
+		Convey("Severity of unknown label is omitted by default", func() {
+			fields := map[string]string{"severity_label": "VERBOSE"}
+			So(processor.setSeverity(fields), ShouldBeNil)
+			So(fields, ShouldNotContainKey, "severity")
+		})
+		Convey("Unknown label gives an error in fail mode", func() {
+			prs, err := processor.parser.configure(map[string]string{unknownSeverityConfig: "fail"})
+			So(err, ShouldBeNil)
+			So(prs.setSeverity(map[string]string{"severity_label": "VERBOSE"}), ShouldNotBeNil)
+		})
+		Convey("Unknown label is given configured severity", func() {
+			prs, err := processor.parser.configure(map[string]string{unknownSeverityConfig: "DEBUG"})
+			So(err, ShouldBeNil)
+			fields := map[string]string{"severity_label": "VERBOSE"}
+			So(prs.setSeverity(fields), ShouldBeNil)
+			So(fields["severity"], ShouldEqual, "7")
+		})
+		Convey("Configured mapping is used", func() {
+			prs, err := processor.parser.configure(map[string]string{severityMappingConfig: "VERBOSE=DEBUG"})
+			So(err, ShouldBeNil)
+			fields := map[string]string{"severity_label": "VERBOSE"}
+			So(prs.setSeverity(fields), ShouldBeNil)
+			So(fields["severity"], ShouldEqual, "7")
+		})
+	})
+}
@@ -0,0 +1,86 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"time"
+
+	"github.com/intelsdi-x/snap-plugin-lib-go/v1/plugin"
+)
+
+// parseStats holds counters of processed metrics, they are counted during a Process call and added to running
+// totals of the task (see task.go), so a rate of i.a. unparsable logs might be derived from them
+type parseStats struct {
+	received              int64
+	records               int64
+	parsed                int64
+	filtered              int64
+	suppressed            int64
+	unparsable            int64
+	nonStringData         int64
+	missingLogFile        int64
+	withRequestContext    int64
+	withoutRequestContext int64
+}
+
+// add adds counters of other stats to the counters
+func (s *parseStats) add(other *parseStats) {
+	s.received += other.received
+	s.records += other.records
+	s.parsed += other.parsed
+	s.filtered += other.filtered
+	s.suppressed += other.suppressed
+	s.unparsable += other.unparsable
+	s.nonStringData += other.nonStringData
+	s.missingLogFile += other.missingLogFile
+	s.withRequestContext += other.withRequestContext
+	s.withoutRequestContext += other.withoutRequestContext
+}
+
+// metrics returns counters as metrics /intel/logs-openstack/stats/<counter_name>
+func (s *parseStats) metrics(timestamp time.Time) []plugin.Metric {
+	counters := []struct {
+		name        string
+		value       int64
+		description string
+	}{
+		{"received", s.received, "Number of received metrics"},
+		{"records", s.records, "Number of log records carried by received metrics, a metric might carry a batch of them"},
+		{"parsed", s.parsed, "Number of successfully parsed log records"},
+		{"filtered", s.filtered, "Number of parsed log records dropped because of severity out of configured range"},
+		{"suppressed", s.suppressed, "Number of metrics suppressed as repetitions of the same log"},
+		{"unparsable", s.unparsable, "Number of log records in invalid format"},
+		{"non_string_data", s.nonStringData, "Number of metrics containing data of unexpected type"},
+		{"missing_log_file", s.missingLogFile, "Number of metrics without dynamic element `log_file`"},
+		{"with_request_context", s.withRequestContext, "Number of parsed logs containing request context"},
+		{"without_request_context", s.withoutRequestContext, "Number of parsed logs without request context"},
+	}
+
+	out := make([]plugin.Metric, 0, len(counters))
+	for _, c := range counters {
+		out = append(out, plugin.Metric{
+			Namespace:   plugin.NewNamespace("intel", Name, "stats", c.name),
+			Data:        c.value,
+			Tags:        map[string]string{},
+			Timestamp:   timestamp,
+			Description: c.description,
+		})
+	}
+	return out
+}
@@ -0,0 +1,61 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseStatsMetrics(t *testing.T) {
+	Convey("Get metrics of parse statistics", t, func() {
+		stats := &parseStats{received: 5, records: 4, parsed: 2, filtered: 1, suppressed: 1, unparsable: 1, nonStringData: 1, missingLogFile: 1, withRequestContext: 1, withoutRequestContext: 1}
+		timestamp := time.Now()
+		mts := stats.metrics(timestamp)
+		So(mts, ShouldHaveLength, 10)
+
+		values := map[string]interface{}{}
+		for _, mt := range mts {
+			So(mt.Namespace.Strings()[:3], ShouldResemble, []string{"intel", "logs-openstack", "stats"})
+			So(mt.Timestamp, ShouldResemble, timestamp)
+			values[mt.Namespace.Strings()[3]] = mt.Data
+		}
+		So(values, ShouldResemble, map[string]interface{}{
+			"received":                int64(5),
+			"records":                 int64(4),
+			"parsed":                  int64(2),
+			"filtered":                int64(1),
+			"suppressed":              int64(1),
+			"unparsable":              int64(1),
+			"non_string_data":         int64(1),
+			"missing_log_file":        int64(1),
+			"with_request_context":    int64(1),
+			"without_request_context": int64(1),
+		})
+	})
+	Convey("Add counters of parse statistics", t, func() {
+		totals := &parseStats{received: 5, unparsable: 1}
+		totals.add(&parseStats{received: 2, parsed: 1, unparsable: 1})
+		So(*totals, ShouldResemble, parseStats{received: 7, parsed: 1, unparsable: 2})
+	})
+}
@@ -0,0 +1,127 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/intelsdi-x/snap-plugin-lib-go/v1/plugin"
+)
+
+const (
+	// defaultSuppressionWindow is a default period (in seconds) in which repetitions of an event are counted
+	defaultSuppressionWindow = 60
+	// defaultSuppressionLimit is a default number of occurrences of an event emitted within the period
+	defaultSuppressionLimit = 10
+)
+
+// suppressor counts occurrences of events across Process calls and suppresses the ones exceeding the limit
+// within a window; events are identified by the metric's namespace and fingerprint of the message
+type suppressor struct {
+	windows map[string]*suppressionWindow
+	mutex   sync.Mutex
+	// now returns current time, it is used to open and close windows
+	now func() time.Time
+}
+
+// suppressionWindow holds occurrences of an event since the window was opened, the window closes after its duration
+type suppressionWindow struct {
+	opened      time.Time
+	duration    time.Duration
+	occurrences int
+	suppressed  int
+	// last holds the last suppressed metric, it is the base of the summary metric
+	last plugin.Metric
+}
+
+func newSuppressor() *suppressor {
+	return &suppressor{
+		windows: map[string]*suppressionWindow{},
+		now:     time.Now,
+	}
+}
+
+// suppress returns the metric unless it exceeds the limit of occurrences of its event within the window,
+// a summary of the previous window of the event is returned before the metric if that window is closed;
+// true is returned if the metric is suppressed
+func (s *suppressor) suppress(m plugin.Metric, window time.Duration, limit int) ([]plugin.Metric, bool) {
+	data, ok := m.Data.(string)
+	if !ok {
+		return []plugin.Metric{m}, false
+	}
+	key := strings.Join(m.Namespace.Strings(), "/") + "|" + getFingerprint(data, m.Tags)["fingerprint"]
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	out := []plugin.Metric{}
+	now := s.now()
+
+	w, exist := s.windows[key]
+	if exist && now.Sub(w.opened) > w.duration {
+		if w.suppressed > 0 {
+			out = append(out, w.summary())
+		}
+		exist = false
+	}
+	if !exist {
+		w = &suppressionWindow{opened: now, duration: window}
+		s.windows[key] = w
+	}
+
+	w.occurrences++
+	if w.occurrences <= limit {
+		return append(out, m), false
+	}
+	w.suppressed++
+	w.last = m
+	return out, true
+}
+
+// flush returns summaries of windows which suppressed metrics since the last flush and removes closed windows
+func (s *suppressor) flush() []plugin.Metric {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	out := []plugin.Metric{}
+	now := s.now()
+	for key, w := range s.windows {
+		if w.suppressed > 0 {
+			out = append(out, w.summary())
+			w.suppressed = 0
+		}
+		if now.Sub(w.opened) > w.duration {
+			delete(s.windows, key)
+		}
+	}
+	return out
+}
+
+// summary returns the last suppressed metric tagged with a number of metrics suppressed since the last summary
+func (w *suppressionWindow) summary() plugin.Metric {
+	m := w.last
+	tags := make(map[string]string, len(m.Tags)+1)
+	mergeMaps(tags, m.Tags)
+	tags["suppressed_count"] = strconv.Itoa(w.suppressed)
+	m.Tags = tags
+	return m
+}
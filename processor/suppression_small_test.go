@@ -0,0 +1,89 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/intelsdi-x/snap-plugin-lib-go/v1/plugin"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSuppressor(t *testing.T) {
+	Convey("Create suppressor", t, func() {
+		now := time.Date(2016, 12, 7, 3, 26, 24, 0, time.UTC)
+		s := newSuppressor()
+		s.now = func() time.Time { return now }
+		window := time.Minute
+
+		line := func(msg string) plugin.Metric {
+			mt := createMockMetric("neutron-l3-agent.log", msg)
+			mt.Tags["logger"] = "openstack.neutron"
+			return mt
+		}
+		suppress := func(mt plugin.Metric) []plugin.Metric {
+			out, _ := s.suppress(mt, window, 2)
+			return out
+		}
+
+		Convey("occurrences of an event exceeding the limit should be suppressed", func() {
+			So(suppress(line("Failed to connect to 10.0.0.1")), ShouldHaveLength, 1)
+			So(suppress(line("Failed to connect to 10.0.0.2")), ShouldHaveLength, 1)
+			out, suppressed := s.suppress(line("Failed to connect to 10.0.0.3"), window, 2)
+			So(out, ShouldBeEmpty)
+			So(suppressed, ShouldBeTrue)
+			So(suppress(line("Failed to connect to 10.0.0.4")), ShouldBeEmpty)
+
+			Convey("other events should not be suppressed", func() {
+				So(suppress(line("Router 5 updated")), ShouldHaveLength, 1)
+			})
+			Convey("summary should be emitted when the window closes", func() {
+				now = now.Add(2 * window)
+				out := suppress(line("Failed to connect to 10.0.0.5"))
+				So(out, ShouldHaveLength, 2)
+				So(out[0].Data, ShouldEqual, "Failed to connect to 10.0.0.4")
+				So(out[0].Tags["suppressed_count"], ShouldEqual, "2")
+				So(out[1].Tags, ShouldNotContainKey, "suppressed_count")
+			})
+			Convey("summary should be emitted when suppressor is flushed", func() {
+				out := s.flush()
+				So(out, ShouldHaveLength, 1)
+				So(out[0].Tags["suppressed_count"], ShouldEqual, "2")
+				So(s.flush(), ShouldBeEmpty)
+
+				Convey("and the window should be kept until it closes", func() {
+					So(suppress(line("Failed to connect to 10.0.0.5")), ShouldBeEmpty)
+					now = now.Add(2 * window)
+					So(s.flush(), ShouldHaveLength, 1)
+					So(s.windows, ShouldBeEmpty)
+				})
+			})
+			Convey("window should close after its own duration", func() {
+				out, _ := s.suppress(line("Instance 5 spawned"), 10*window, 2)
+				So(out, ShouldHaveLength, 1)
+				now = now.Add(2 * window)
+				s.flush()
+				So(s.windows, ShouldHaveLength, 1)
+			})
+		})
+	})
+}
@@ -0,0 +1,178 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ***	PATTERNS FOR SYSLOG MESSAGE   ***
+	// 	Openstack services configured with `use_syslog = True` log via syslog, messages forwarded i.a. by rsyslog have
+	// 	a header in one of the following forms, where `pri` encodes a facility and a severity of message:
+	//
+	// 	a) RFC 3164, the timestamp lacks a year (rsyslog might write it in RFC 3339 form instead):
+	// 	<<pri>><timestamp> <hostname> <program>[<pid>]: <payload>
+	//
+	// 	Example:	<11>Dec  8 03:18:49 controller-1 nova-api[20]: ERROR nova.api.openstack.extensions some_message
+	//
+	syslog3164Regexp = `^<(?P<syslog_pri>\d{1,3})>(?P<timestamp>[A-Z][a-z]{2} [ \d]\d \d{2}:\d{2}:\d{2}|\d{4}-\d{2}-\d{2}T\S+) ` +
+		`(?P<hostname>\S+) (?P<program>[^\s\[\]:]+)(\[(?P<pid>\d+)\])?: ?(?P<payload>(\n|.)*)`
+
+	// 	b) RFC 5424, where `-` stands for a missing value and the structured data are skipped:
+	// 	<<pri>>1 <timestamp> <hostname> <program> <pid> <msgid> <structured_data> <payload>
+	//
+	// 	Example:	<11>1 2016-12-08T03:18:49.626Z controller-1 nova-api 20 - - ERROR nova.api.openstack.extensions some_message
+	//
+	syslog5424Regexp = `^<(?P<syslog_pri>\d{1,3})>1 (?P<timestamp>\S+) (?P<hostname>\S+) (?P<program>\S+) (?P<pid>\S+) \S+ ` +
+		`(-|(\[([^\]\\]|\\.)*\])+)( (?P<payload>(\n|.)*))?$`
+
+	// 	The payload is either a complete Openstack log or lacks its timestamp and pid, as they are held by the header:
+	// 	<severity_label> <python_module> <payload>
+	syslogPayloadRegexp = `^(?P<severity_label>[A-Z]+) (?P<python_module>[A-Za-z_][\w.]*) (?P<payload>(\n|.)*)`
+
+	// syslogPriRegexp matches a beginning of syslog message
+	syslogPriRegexp = `^<\d{1,3}>`
+
+	// syslogTimeFormat is a format of RFC 3164 timestamp
+	syslogTimeFormat = "Jan _2 15:04:05"
+
+	// syslogMaxPri is the greatest valid value of pri, i.e. of facility local7 and severity debug
+	syslogMaxPri = 191
+)
+
+var (
+	syslog3164Rgx    = regexp.MustCompile(syslog3164Regexp)
+	syslog5424Rgx    = regexp.MustCompile(syslog5424Regexp)
+	syslogPayloadRgx = regexp.MustCompile(syslogPayloadRegexp)
+	syslogPriRgx     = regexp.MustCompile(syslogPriRegexp)
+)
+
+// syslogFacilities lists names of syslog facilities by their codes
+var syslogFacilities = []string{
+	"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news", "uucp", "cron", "authpriv", "ftp",
+	"ntp", "security", "console", "solaris-cron", "local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+}
+
+// syslogSeverities lists labels of syslog severities by their levels, they are known labels of severity map
+var syslogSeverities = []string{"EMERG", "ALERT", "CRIT", "ERR", "WARNING", "NOTICE", "INFO", "DEBUG"}
+
+// syslogFormat is a format of Openstack logs forwarded by syslog
+type syslogFormat struct{}
+
+func (syslogFormat) parse(p *parser, data string) (timestamp time.Time, msg string, fields map[string]string, err error) {
+	header, err := parse(data, syslog5424Rgx)
+	if err != nil {
+		if header, err = parse(data, syslog3164Rgx); err != nil {
+			return
+		}
+	}
+	for name, val := range header {
+		// skip values which are missing in RFC 5424 header
+		if val == "-" {
+			delete(header, name)
+		}
+	}
+
+	pri, _ := strconv.Atoi(header["syslog_pri"])
+	if pri > syslogMaxPri {
+		err = fmt.Errorf("Invalid syslog priority `%d`", pri)
+		return
+	}
+	tags := map[string]string{
+		"syslog_facility": syslogFacilities[pri/8],
+		"syslog_severity": strconv.Itoa(pri % 8),
+	}
+	for _, name := range []string{"hostname", "program", "pid"} {
+		if val, exist := header[name]; exist {
+			tags[name] = val
+		}
+	}
+
+	// the payload might start with BOM in RFC 5424 message
+	payload := strings.TrimPrefix(header["payload"], "\ufeff")
+
+	if p.logRgx.MatchString(payload) || isJSONLog(payload) {
+		// complete Openstack log with own timestamp and pid
+		if timestamp, msg, fields, err = (openstackFormat{}).parse(p, payload); err != nil {
+			return
+		}
+		for name, val := range tags {
+			if _, exist := fields[name]; !exist {
+				fields[name] = val
+			}
+		}
+		return timestamp, msg, fields, nil
+	}
+
+	if ts, exist := header["timestamp"]; exist {
+		timestamp, err = parseSyslogTimestamp(ts, p.location, time.Now())
+		if err != nil {
+			return
+		}
+	} else {
+		err = fmt.Errorf("No timestamp in log")
+		return
+	}
+
+	if fields, err = parse(payload, syslogPayloadRgx); err == nil {
+		msg = fields["payload"]
+		delete(fields, "payload")
+	} else {
+		// a message which is not formatted by oslo.log, its severity is taken from pri
+		fields = map[string]string{"severity_label": syslogSeverities[pri%8]}
+		msg = payload
+	}
+	mergeMaps(fields, tags)
+
+	if err = p.setSeverity(fields); err != nil {
+		return
+	}
+	p.addRequestContexts(msg, fields)
+	return timestamp, msg, fields, nil
+}
+
+func (syslogFormat) startsRecord(line string) bool {
+	return syslogPriRgx.MatchString(line)
+}
+
+// parseSyslogTimestamp parses a timestamp of syslog message, an RFC 3164 timestamp lacks a year, so the year is chosen
+// to put the timestamp within six months from now, i.a. logs of December received in January are of the previous year
+func parseSyslogTimestamp(ts string, location *time.Location, now time.Time) (time.Time, error) {
+	if !strings.Contains(ts, "T") {
+		t, err := time.ParseInLocation(syslogTimeFormat, ts, location)
+		if err != nil {
+			return t, err
+		}
+		now = now.In(location)
+		t = time.Date(now.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, location)
+		switch {
+		case t.After(now.AddDate(0, 6, 0)):
+			t = t.AddDate(-1, 0, 0)
+		case t.Before(now.AddDate(0, -6, 0)):
+			t = t.AddDate(1, 0, 0)
+		}
+		return t, nil
+	}
+	return parseTimestamp(ts, location)
+}
@@ -0,0 +1,133 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSyslogFormat(t *testing.T) {
+	Convey("Create logs-openstack processor", t, func() {
+		processor := New()
+		So(processor, ShouldNotBeNil)
+		format := syslogFormat{}
+
+		Convey("RFC 3164 message should be parsed", func() {
+			timestamp, msg, fields, err := format.parse(processor.parser, "<11>Dec  8 03:18:49 controller-1 nova-api[20]: "+
+				"ERROR nova.api.openstack.extensions [req-0c0b761c-47b0-4bf5-832c-89ef048fa56a - - - - -] Unexpected exception in API method")
+			So(err, ShouldBeNil)
+			So(timestamp.Month(), ShouldEqual, time.December)
+			So(timestamp.Day(), ShouldEqual, 8)
+			So(timestamp.Format("15:04:05"), ShouldEqual, "03:18:49")
+			So(msg, ShouldEqual, "[req-0c0b761c-47b0-4bf5-832c-89ef048fa56a - - - - -] Unexpected exception in API method")
+			So(fields, ShouldResemble, map[string]string{
+				"syslog_facility": "user",
+				"syslog_severity": "3",
+				"hostname":        "controller-1",
+				"program":         "nova-api",
+				"pid":             "20",
+				"severity_label":  "ERROR",
+				"severity":        "3",
+				"python_module":   "nova.api.openstack.extensions",
+				"request_id":      "0c0b761c-47b0-4bf5-832c-89ef048fa56a",
+			})
+		})
+		Convey("RFC 5424 message should be parsed", func() {
+			timestamp, msg, fields, err := format.parse(processor.parser, "<134>1 2016-12-08T03:18:49.626Z controller-1 neutron-server 25 - "+
+				`[origin ip="10.0.0.1"] INFO neutron.wsgi [-] 10.0.0.2 "GET /v2.0/networks HTTP/1.1" status: 200 len: 3012 time: 0.0512`)
+			So(err, ShouldBeNil)
+			So(timestamp.Equal(time.Date(2016, 12, 8, 3, 18, 49, 626000000, time.UTC)), ShouldBeTrue)
+			So(msg, ShouldStartWith, "[-] 10.0.0.2")
+			So(fields["syslog_facility"], ShouldEqual, "local0")
+			So(fields["syslog_severity"], ShouldEqual, "6")
+			So(fields["program"], ShouldEqual, "neutron-server")
+			So(fields["pid"], ShouldEqual, "25")
+			So(fields["python_module"], ShouldEqual, "neutron.wsgi")
+			So(fields["http_status"], ShouldEqual, "200")
+		})
+		Convey("missing values of RFC 5424 message should be skipped", func() {
+			_, msg, fields, err := format.parse(processor.parser, "<134>1 2016-12-08T03:18:49.626Z - nova-api - - - INFO nova.wsgi Starting")
+			So(err, ShouldBeNil)
+			So(msg, ShouldEqual, "Starting")
+			So(fields, ShouldNotContainKey, "hostname")
+			So(fields, ShouldNotContainKey, "pid")
+		})
+		Convey("complete Openstack log should be parsed with its own timestamp", func() {
+			timestamp, msg, fields, err := format.parse(processor.parser, "<11>Dec  8 03:18:49 controller-1 nova-api[20]: "+
+				"2016-12-08 03:18:49.626 20 ERROR nova.api.openstack.extensions [-] Unexpected exception in API method")
+			So(err, ShouldBeNil)
+			So(timestamp, ShouldResemble, time.Date(2016, 12, 8, 3, 18, 49, 626000000, time.Local))
+			So(msg, ShouldEqual, "[-] Unexpected exception in API method")
+			So(fields["python_module"], ShouldEqual, "nova.api.openstack.extensions")
+			So(fields["hostname"], ShouldEqual, "controller-1")
+		})
+		Convey("severity of message not formatted by oslo.log should be taken from priority", func() {
+			_, msg, fields, err := format.parse(processor.parser, "<28>Dec  8 03:18:49 compute-1 libvirtd[1203]: internal error: End of file from qemu monitor")
+			So(err, ShouldBeNil)
+			So(msg, ShouldEqual, "internal error: End of file from qemu monitor")
+			So(fields["syslog_facility"], ShouldEqual, "daemon")
+			So(fields["severity_label"], ShouldEqual, "WARNING")
+			So(fields["severity"], ShouldEqual, "4")
+		})
+		Convey("message with invalid priority should give an error", func() {
+			_, _, _, err := format.parse(processor.parser, "<192>Dec  8 03:18:49 controller-1 nova-api[20]: ERROR nova.api some_message")
+			So(err, ShouldNotBeNil)
+		})
+		Convey("log in other format should give an error", func() {
+			_, _, _, err := format.parse(processor.parser, "2016-12-07 03:26:24.254 7 INFO nova.console.websocketproxy [-] handler exception")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestParseSyslogTimestamp(t *testing.T) {
+	Convey("Parse timestamps of syslog messages", t, func() {
+		now := time.Date(2017, 1, 2, 10, 0, 0, 0, time.UTC)
+
+		Convey("timestamp should be set in the current year", func() {
+			timestamp, err := parseSyslogTimestamp("Jan  2 09:59:58", time.UTC, now)
+			So(err, ShouldBeNil)
+			So(timestamp, ShouldResemble, time.Date(2017, 1, 2, 9, 59, 58, 0, time.UTC))
+		})
+		Convey("timestamp of the end of year should be set in the previous year", func() {
+			timestamp, err := parseSyslogTimestamp("Dec 31 23:59:59", time.UTC, now)
+			So(err, ShouldBeNil)
+			So(timestamp, ShouldResemble, time.Date(2016, 12, 31, 23, 59, 59, 0, time.UTC))
+		})
+		Convey("timestamp of the beginning of year should be set in the next year", func() {
+			timestamp, err := parseSyslogTimestamp("Jan  1 00:00:01", time.UTC, time.Date(2016, 12, 31, 23, 59, 0, 0, time.UTC))
+			So(err, ShouldBeNil)
+			So(timestamp, ShouldResemble, time.Date(2017, 1, 1, 0, 0, 1, 0, time.UTC))
+		})
+		Convey("RFC 3339 timestamp should be parsed", func() {
+			timestamp, err := parseSyslogTimestamp("2016-12-08T03:18:49.626+01:00", time.UTC, now)
+			So(err, ShouldBeNil)
+			So(timestamp.Equal(time.Date(2016, 12, 8, 2, 18, 49, 626000000, time.UTC)), ShouldBeTrue)
+		})
+		Convey("invalid timestamp should give an error", func() {
+			_, err := parseSyslogTimestamp("Dec 32 03:18:49", time.UTC, now)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,104 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/intelsdi-x/snap-plugin-lib-go/v1/plugin"
+)
+
+// taskState holds state kept between Process calls of a task, so logs of one task are never emitted by Process calls
+// of another one; a task is identified by its config, tasks with the same config share the state
+type taskState struct {
+	// tracebacks joins lines of tracebacks when `multiline_traceback` is enabled
+	tracebacks *tracebackJoiner
+	// suppressions counts repetitions of logs when `suppression` is enabled
+	suppressions *suppressor
+	// stats holds running totals of counters when `stats_metrics` is enabled
+	stats *parseStats
+	mutex sync.Mutex
+}
+
+func newTaskState() *taskState {
+	return &taskState{
+		tracebacks:   newTracebackJoiner(),
+		suppressions: newSuppressor(),
+		stats:        &parseStats{},
+	}
+}
+
+// getTaskState returns the state of the task with the given config, it is created by the first Process call of the task
+func (p *Plugin) getTaskState(cfg plugin.Config) *taskState {
+	key := taskKey(cfg)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if task, exist := p.tasks[key]; exist {
+		return task
+	}
+	task := newTaskState()
+	p.tasks[key] = task
+	return task
+}
+
+// suppress returns metrics which are not suppressed as repetitions of the same log, when suppression is enabled
+func (t *taskState) suppress(mts []plugin.Metric, opts *options, stats *parseStats) []plugin.Metric {
+	if !opts.suppression {
+		return mts
+	}
+
+	out := make([]plugin.Metric, 0, len(mts))
+	for _, m := range mts {
+		ready, suppressed := t.suppressions.suppress(m, opts.suppressionWindow, opts.suppressionLimit)
+		if suppressed {
+			stats.suppressed++
+		}
+		out = append(out, ready...)
+	}
+	return out
+}
+
+// addStats adds counters of a Process call to running totals of the task and returns a copy of the totals
+func (t *taskState) addStats(stats *parseStats) *parseStats {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.stats.add(stats)
+	totals := *t.stats
+	return &totals
+}
+
+// taskKey returns a key identifying a task by all items of its config
+func taskKey(cfg plugin.Config) string {
+	names := make([]string, 0, len(cfg))
+	for name := range cfg {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	key := ""
+	for _, name := range names {
+		key += fmt.Sprintf("%s=%v;", name, cfg[name])
+	}
+	return key
+}
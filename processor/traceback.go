@@ -0,0 +1,215 @@
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/intelsdi-x/snap-plugin-lib-go/v1/plugin"
+)
+
+const (
+	// ***	PATTERN FOR PYTHON TRACEBACK   ***
+	// 	Openstack services log a traceback as many consecutive lines, each of them repeats the log prefix:
+	//
+	// 	2016-12-08 03:18:49.626 20 ERROR nova.api.openstack.extensions [req-0c0b761c-47b0-4bf5-832c-89ef048fa56a - - - - -] Unexpected exception in API method
+	// 	2016-12-08 03:18:49.626 20 ERROR nova.api.openstack.extensions Traceback (most recent call last):
+	// 	2016-12-08 03:18:49.626 20 ERROR nova.api.openstack.extensions   File "/usr/lib/python2.7/site-packages/nova/api/openstack/extensions.py", line 478, in wrapped
+	// 	2016-12-08 03:18:49.626 20 ERROR nova.api.openstack.extensions     return f(*args, **kwargs)
+	// 	2016-12-08 03:18:49.626 20 ERROR nova.api.openstack.extensions ValueError: invalid literal for int() with base 10: 'abc'
+	//
+	// 	**Notice** that older releases log the traceback lines with `TRACE` severity label instead of `ERROR`
+	// 	**Notice** that nova-compute precedes the payload of traceback lines with `[instance: <uuid>]` context
+	// 	as `logging_exception_prefix` ends with `%(instance)s`, such leading contexts are skipped when lines are checked
+	tracebackHeader = "Traceback (most recent call last):"
+	// 	Chained exceptions are separated by one of these lines followed by another traceback
+	chainedExceptionCause   = "The above exception was the direct cause of the following exception:"
+	chainedExceptionContext = "During handling of the above exception, another exception occurred:"
+
+	exceptionRegexp = `^(?P<exception_type>[A-Za-z_][\w.]*)(: (?P<exception_message>.*))?$`
+	// 	Leading contexts in brackets, e.g. `[instance: 4a6e1c2b-5b36-4a32-9c7e-21c8e7f4a1d3] `
+	tracebackContextRegexp = `^(\[[^\]]*\] )+`
+)
+
+var exceptionRgx = regexp.MustCompile(exceptionRegexp)
+
+var tracebackContextRgx = regexp.MustCompile(tracebackContextRegexp)
+
+// tracebackJoiner joins consecutive lines of a traceback into one metric, lines are joined when they come
+// from the same log file, pid and python module and have the same or no request id
+type tracebackJoiner struct {
+	// pending holds tracebacks which might be continued, indexed by log file and pid
+	pending map[string]*traceback
+	mutex   sync.Mutex
+}
+
+// traceback holds a processed metric of the first line and payloads of all joined lines
+type traceback struct {
+	metric    plugin.Metric
+	lines     []string
+	hasHeader bool
+	// indented is set when the last joined line is indented, so it is a frame of the traceback
+	indented bool
+	// touched is set when the traceback was started or continued during the current Process call
+	touched bool
+}
+
+func newTracebackJoiner() *tracebackJoiner {
+	return &tracebackJoiner{pending: map[string]*traceback{}}
+}
+
+// join adds a processed metric to the pending traceback which it continues or, if the metric might start
+// a traceback, holds it as a new pending one; metrics ready to be emitted are returned
+func (j *tracebackJoiner) join(m plugin.Metric) []plugin.Metric {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	out := []plugin.Metric{}
+	key := tracebackKey(m)
+	msg, _ := m.Data.(string)
+
+	if tb, exist := j.pending[key]; exist {
+		if tb.continues(m, stripTracebackContext(msg)) {
+			tb.add(m, msg)
+			return out
+		}
+		// a line which does not continue the traceback closes it
+		out = append(out, tb.close())
+		delete(j.pending, key)
+	}
+
+	if !startsTraceback(m, stripTracebackContext(msg)) {
+		return append(out, m)
+	}
+
+	tb := &traceback{metric: m}
+	tb.add(m, msg)
+	j.pending[key] = tb
+
+	return out
+}
+
+// flush closes and returns pending lines at the end of a Process call, only tracebacks which were started
+// by the header and continued during the current Process call are kept as they might be continued by the next one
+func (j *tracebackJoiner) flush() []plugin.Metric {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	out := []plugin.Metric{}
+	for key, tb := range j.pending {
+		if tb.touched && tb.hasHeader {
+			tb.touched = false
+			continue
+		}
+		out = append(out, tb.close())
+		delete(j.pending, key)
+	}
+	return out
+}
+
+// add appends payload of a line to the traceback
+func (t *traceback) add(m plugin.Metric, msg string) {
+	t.lines = append(t.lines, msg)
+	line := stripTracebackContext(msg)
+	t.indented = strings.HasPrefix(line, " ")
+	t.touched = true
+	if strings.Contains(line, tracebackHeader) {
+		t.hasHeader = true
+	}
+}
+
+// continues returns true if a line is a continuation of the traceback, msg is the payload without leading contexts
+func (t *traceback) continues(m plugin.Metric, msg string) bool {
+	if m.Tags["python_module"] != t.metric.Tags["python_module"] {
+		return false
+	}
+	if requestID, ok := m.Tags["request_id"]; ok && requestID != t.metric.Tags["request_id"] {
+		return false
+	}
+
+	switch {
+	case m.Tags["severity_label"] == "TRACE":
+		return true
+	case strings.HasPrefix(msg, tracebackHeader):
+		return true
+	case t.hasHeader && (strings.HasPrefix(msg, " ") || t.indented):
+		// frames are indented and the exception line follows the last frame
+		return true
+	case t.hasHeader && (msg == "" || msg == chainedExceptionCause || msg == chainedExceptionContext):
+		return true
+	}
+	return false
+}
+
+// close returns the metric of the first line with data holding all joined lines and tags describing the exception
+func (t *traceback) close() plugin.Metric {
+	m := t.metric
+	data := strings.Join(t.lines, "\n")
+	m.Data = data
+
+	if t.hasHeader {
+		tags := make(map[string]string, len(m.Tags)+2)
+		mergeMaps(tags, m.Tags)
+		mergeMaps(tags, getException(data))
+		m.Tags = tags
+	}
+	return m
+}
+
+// startsTraceback returns true if a line might be followed by a traceback
+func startsTraceback(m plugin.Metric, msg string) bool {
+	switch m.Tags["severity_label"] {
+	case "ERROR", "CRITICAL", "TRACE":
+		return true
+	}
+	return strings.Contains(msg, tracebackHeader)
+}
+
+// getException returns type and message of the last exception found in a traceback or nil if there is none
+func getException(data string) map[string]string {
+	var exception map[string]string
+
+	inTraceback := false
+	for _, line := range strings.Split(data, "\n") {
+		line = stripTracebackContext(line)
+		if strings.Contains(line, tracebackHeader) {
+			inTraceback = true
+			continue
+		}
+		if !inTraceback || strings.HasPrefix(line, " ") {
+			continue
+		}
+		if fields, err := parse(strings.TrimSpace(line), exceptionRgx); err == nil {
+			exception = fields
+		}
+	}
+	return exception
+}
+
+// stripTracebackContext returns payload of a line without leading contexts in brackets
+func stripTracebackContext(msg string) string {
+	return tracebackContextRgx.ReplaceAllString(msg, "")
+}
+
+// tracebackKey returns a key identifying consecutive lines logged by the same process
+func tracebackKey(m plugin.Metric) string {
+	return strings.Join(m.Namespace.Strings(), "/") + "|" + m.Tags["pid"]
+}
@@ -0,0 +1,138 @@
+// +build small
+
+/*
+http://www.apache.org/licenses/LICENSE-2.0.txt
+
+	Copyright 2016 Intel Corporation
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/intelsdi-x/snap-plugin-lib-go/v1/plugin"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetException(t *testing.T) {
+	Convey("Get exception from traceback", t, func() {
+		Convey("should return nil when there is no traceback", func() {
+			So(getException("ValueError: invalid literal"), ShouldBeNil)
+		})
+		Convey("should return type and message of exception", func() {
+			exception := getException("Unexpected exception in API method\n" +
+				"Traceback (most recent call last):\n" +
+				"  File \"/usr/lib/python2.7/site-packages/nova/api/openstack/extensions.py\", line 478, in wrapped\n" +
+				"    return f(*args, **kwargs)\n" +
+				"ValueError: invalid literal for int() with base 10: 'abc'")
+			So(exception, ShouldResemble, map[string]string{
+				"exception_type":    "ValueError",
+				"exception_message": "invalid literal for int() with base 10: 'abc'",
+			})
+		})
+		Convey("should return the last exception of chained tracebacks", func() {
+			exception := getException("Traceback (most recent call last):\n" +
+				"  File \"a.py\", line 1, in <module>\n" +
+				"KeyError: 'id'\n" +
+				"During handling of the above exception, another exception occurred:\n" +
+				"Traceback (most recent call last):\n" +
+				"  File \"a.py\", line 3, in <module>\n" +
+				"nova.exception.InstanceNotFound: Instance 1 could not be found.")
+			So(exception["exception_type"], ShouldEqual, "nova.exception.InstanceNotFound")
+			So(exception["exception_message"], ShouldEqual, "Instance 1 could not be found.")
+		})
+	})
+}
+
+func TestTracebackJoiner(t *testing.T) {
+	Convey("Create traceback joiner", t, func() {
+		joiner := newTracebackJoiner()
+		ts := time.Date(2016, 12, 8, 3, 18, 49, 626000000, time.UTC)
+		line := func(label string, msg string, tags map[string]string) plugin.Metric {
+			mt := createMockMetric("nova-api.log", msg)
+			mt.Timestamp = ts
+			mt.Tags["pid"] = "20"
+			mt.Tags["python_module"] = "nova.api.openstack.extensions"
+			mt.Tags["severity_label"] = label
+			mergeMaps(mt.Tags, tags)
+			return mt
+		}
+
+		Convey("line which cannot start a traceback should be returned immediately", func() {
+			out := joiner.join(line("INFO", "[-] Stopping WSGI server.", nil))
+			So(out, ShouldHaveLength, 1)
+			So(joiner.flush(), ShouldBeEmpty)
+		})
+		Convey("lines of a traceback should be joined into one metric", func() {
+			requestID := map[string]string{"request_id": "0c0b761c-47b0-4bf5-832c-89ef048fa56a"}
+			So(joiner.join(line("ERROR", "[req-0c0b761c-47b0-4bf5-832c-89ef048fa56a - - - - -] Unexpected exception", requestID)), ShouldBeEmpty)
+			So(joiner.join(line("ERROR", "Traceback (most recent call last):", nil)), ShouldBeEmpty)
+			So(joiner.join(line("ERROR", "  File \"extensions.py\", line 478, in wrapped", nil)), ShouldBeEmpty)
+			So(joiner.join(line("ERROR", "ValueError: invalid literal", nil)), ShouldBeEmpty)
+
+			Convey("and emitted when a line which does not continue it comes", func() {
+				out := joiner.join(line("INFO", "[-] Stopping WSGI server.", nil))
+				So(out, ShouldHaveLength, 2)
+				So(out[0].Data, ShouldEqual, "[req-0c0b761c-47b0-4bf5-832c-89ef048fa56a - - - - -] Unexpected exception\n"+
+					"Traceback (most recent call last):\n"+
+					"  File \"extensions.py\", line 478, in wrapped\n"+
+					"ValueError: invalid literal")
+				So(out[0].Tags["request_id"], ShouldEqual, "0c0b761c-47b0-4bf5-832c-89ef048fa56a")
+				So(out[0].Tags["exception_type"], ShouldEqual, "ValueError")
+				So(out[0].Tags["exception_message"], ShouldEqual, "invalid literal")
+				So(out[1].Data, ShouldEqual, "[-] Stopping WSGI server.")
+			})
+			Convey("and kept pending until a Process call which does not continue it", func() {
+				So(joiner.flush(), ShouldBeEmpty)
+				out := joiner.flush()
+				So(out, ShouldHaveLength, 1)
+				So(out[0].Tags["exception_type"], ShouldEqual, "ValueError")
+			})
+		})
+		Convey("line without a traceback should be emitted by flush of the same Process call", func() {
+			So(joiner.join(line("ERROR", "[-] Unexpected exception", nil)), ShouldBeEmpty)
+			out := joiner.flush()
+			So(out, ShouldHaveLength, 1)
+			So(out[0].Data, ShouldEqual, "[-] Unexpected exception")
+		})
+		Convey("lines with instance context should be joined", func() {
+			instance := "[instance: 4a6e1c2b-5b36-4a32-9c7e-21c8e7f4a1d3] "
+			So(joiner.join(line("ERROR", instance+"Traceback (most recent call last):", nil)), ShouldBeEmpty)
+			So(joiner.join(line("ERROR", instance+"  File \"manager.py\", line 2078, in _build_resources", nil)), ShouldBeEmpty)
+			So(joiner.join(line("ERROR", instance+"RescheduledException: Build was re-scheduled", nil)), ShouldBeEmpty)
+			joiner.flush()
+			out := joiner.flush()
+			So(out, ShouldHaveLength, 1)
+			So(out[0].Tags["exception_type"], ShouldEqual, "RescheduledException")
+			So(out[0].Tags["exception_message"], ShouldEqual, "Build was re-scheduled")
+		})
+		Convey("lines with TRACE severity label should be joined", func() {
+			So(joiner.join(line("TRACE", "Traceback (most recent call last):", nil)), ShouldBeEmpty)
+			So(joiner.join(line("TRACE", "  File \"extensions.py\", line 478, in wrapped", nil)), ShouldBeEmpty)
+			joiner.flush()
+			out := joiner.flush()
+			So(out, ShouldHaveLength, 1)
+			So(out[0].Data, ShouldEqual, "Traceback (most recent call last):\n  File \"extensions.py\", line 478, in wrapped")
+		})
+		Convey("lines with a different request id should not be joined", func() {
+			So(joiner.join(line("ERROR", "first", map[string]string{"request_id": "a"})), ShouldBeEmpty)
+			out := joiner.join(line("ERROR", "Traceback (most recent call last):", map[string]string{"request_id": "b"}))
+			So(out, ShouldHaveLength, 1)
+			So(out[0].Data, ShouldEqual, "first")
+		})
+	})
+}